@@ -0,0 +1,123 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// Background is the background color of a terminal.
+type Background struct {
+	R, G, B uint8
+}
+
+// IsLight returns true if the perceived brightness of the background is more
+// than half its maximum.
+func (b Background) IsLight() bool {
+	// https://www.w3.org/TR/AERT/#color-contrast
+	brightness := (299*int(b.R) + 587*int(b.G) + 114*int(b.B)) / 1000
+	return brightness > 127
+}
+
+// IsDark returns true if the background is not light.
+func (b Background) IsDark() bool {
+	return !b.IsLight()
+}
+
+// Standard xterm colors indexed by COLORFGBG.
+var indexedBackgrounds = [...]Background{
+	{0x00, 0x00, 0x00},
+	{0xcd, 0x00, 0x00},
+	{0x00, 0xcd, 0x00},
+	{0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee},
+	{0xcd, 0x00, 0xcd},
+	{0x00, 0xcd, 0xcd},
+	{0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f},
+	{0xff, 0x00, 0x00},
+	{0x00, 0xff, 0x00},
+	{0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff},
+	{0xff, 0x00, 0xff},
+	{0x00, 0xff, 0xff},
+	{0xff, 0xff, 0xff},
+}
+
+var backgroundResponse = regexp.MustCompile(`\x1b\]11;rgba?:([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})(?:/[0-9a-fA-F]{1,4})?(?:\x07|\x1b\\)`)
+
+// Background gets the background color of the terminal. The terminal is
+// queried once using OSC 11 if both Stdin and Stdout are terminals, falling
+// back to the COLORFGBG environment variable.
+func (c *con) Background() (Background, error) {
+	c.backgroundOnce.Do(func() {
+		if c.background != nil {
+			return
+		}
+
+		if resp, err := c.query(ansi.OSC+"11;?"+ansi.ST, backgroundResponse); err == nil && resp != nil {
+			if bg, ok := parseBackgroundResponse(resp); ok {
+				c.background = &bg
+				return
+			}
+		}
+
+		if bg, ok := parseColorFgBg(os.Getenv("COLORFGBG")); ok {
+			c.background = &bg
+		}
+	})
+
+	if c.background == nil {
+		return Background{}, fmt.Errorf("cannot determine background color")
+	}
+
+	return *c.background, nil
+}
+
+func (c *con) isLightBackground() bool {
+	bg, err := c.Background()
+	return err == nil && bg.IsLight()
+}
+
+// parseBackgroundResponse parses an OSC 11 response
+// e.g., "\x1b]11;rgb:ffff/ffff/ffff\x1b\\".
+func parseBackgroundResponse(resp []byte) (Background, bool) {
+	m := backgroundResponse.FindSubmatch(resp)
+	if m == nil {
+		return Background{}, false
+	}
+
+	var rgb [3]uint8
+	for i, hex := range m[1:4] {
+		v, err := strconv.ParseUint(string(hex), 16, 16)
+		if err != nil {
+			return Background{}, false
+		}
+
+		// Scale 1 to 4 hex digits to 8 bits.
+		limit := uint64(1)<<(4*len(hex)) - 1
+		rgb[i] = uint8((v*255 + limit/2) / limit)
+	}
+
+	return Background{R: rgb[0], G: rgb[1], B: rgb[2]}, true
+}
+
+// parseColorFgBg parses the background color index from COLORFGBG
+// e.g., "15;0" or "15;default;0".
+func parseColorFgBg(s string) (Background, bool) {
+	if s == "" {
+		return Background{}, false
+	}
+
+	parts := strings.Split(s, ";")
+	i, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || i < 0 || i >= len(indexedBackgrounds) {
+		return Background{}, false
+	}
+
+	return indexedBackgrounds[i], true
+}
@@ -0,0 +1,173 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/colorscheme"
+)
+
+func TestBackground_IsLight(t *testing.T) {
+	tests := []struct {
+		name string
+		bg   Background
+		want bool
+	}{
+		{name: "black", bg: Background{0, 0, 0}, want: false},
+		{name: "white", bg: Background{255, 255, 255}, want: true},
+		{name: "solarized dark", bg: Background{0x00, 0x2b, 0x36}, want: false},
+		{name: "solarized light", bg: Background{0xfd, 0xf6, 0xe3}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bg.IsLight(); got != tt.want {
+				t.Fatalf("IsLight() = %v, expected %v", got, tt.want)
+			}
+			if got := tt.bg.IsDark(); got == tt.want {
+				t.Fatalf("IsDark() = %v, expected %v", got, !tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBackgroundResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		resp   string
+		want   Background
+		wantOk bool
+	}{
+		{name: "16-bit ST", resp: "\x1b]11;rgb:ffff/8080/0000\x1b\\", want: Background{255, 128, 0}, wantOk: true},
+		{name: "16-bit BEL", resp: "\x1b]11;rgb:0000/2b2b/3636\a", want: Background{0x00, 0x2b, 0x36}, wantOk: true},
+		{name: "8-bit", resp: "\x1b]11;rgb:fd/f6/e3\a", want: Background{0xfd, 0xf6, 0xe3}, wantOk: true},
+		{name: "4-bit", resp: "\x1b]11;rgb:f/0/8\a", want: Background{255, 0, 136}, wantOk: true},
+		{name: "rgba", resp: "\x1b]11;rgba:ffff/ffff/ffff/ffff\a", want: Background{255, 255, 255}, wantOk: true},
+		{name: "invalid", resp: "\x1b]11;?\a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseBackgroundResponse([]byte(tt.resp))
+			if ok != tt.wantOk {
+				t.Fatalf("parseBackgroundResponse() ok = %v, expected %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Fatalf("parseBackgroundResponse() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColorFgBg(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Background
+		wantOk bool
+	}{
+		{value: ""},
+		{value: "15;0", want: Background{0, 0, 0}, wantOk: true},
+		{value: "0;15", want: Background{255, 255, 255}, wantOk: true},
+		{value: "0;default;7", want: Background{0xe5, 0xe5, 0xe5}, wantOk: true},
+		{value: "0;default"},
+		{value: "0;16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseColorFgBg(tt.value)
+			if ok != tt.wantOk {
+				t.Fatalf("parseColorFgBg() ok = %v, expected %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Fatalf("parseColorFgBg() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_Background_query(t *testing.T) {
+	t.Setenv("COLORFGBG", "")
+
+	stdin := bytes.NewBufferString("a\x1b]11;rgb:ffff/ffff/ffff\x1b\\\x1b[?62;22cb")
+	f := Fake(
+		WithStdin(stdin),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	bg, err := f.Background()
+	if err != nil {
+		t.Fatalf("Background() error = %v", err)
+	}
+	if !bg.IsLight() {
+		t.Fatalf("Background() = %v, expected light", bg)
+	}
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b]11;?\x1b\\\x1b[c"; got != want {
+		t.Fatalf("Background() wrote %q, expected %q", got, want)
+	}
+
+	buf := make([]byte, 4)
+	n, _ := f.Stdin().Read(buf)
+	if got := string(buf[:n]); got != "ab" {
+		t.Fatalf("Stdin() = %q, expected unread input %q", got, "ab")
+	}
+}
+
+func TestConsole_Background_unsupported(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+
+	stdin := bytes.NewBufferString("\x1b[?1;2c")
+	f := Fake(
+		WithStdin(stdin),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	bg, err := f.Background()
+	if err != nil {
+		t.Fatalf("Background() error = %v", err)
+	}
+	if bg != (Background{255, 255, 255}) {
+		t.Fatalf("Background() = %v, expected white from COLORFGBG", bg)
+	}
+}
+
+func TestConsole_Background_unknown(t *testing.T) {
+	t.Setenv("COLORFGBG", "")
+
+	f := Fake()
+	if _, err := f.Background(); err == nil {
+		t.Fatal("Background() expected error")
+	}
+
+	stdout, _, _ := f.Buffers()
+	if stdout.Len() > 0 {
+		t.Fatalf("Background() wrote %q, expected nothing when not a TTY", stdout.String())
+	}
+}
+
+func TestWithBackground(t *testing.T) {
+	f := Fake(
+		WithBackground(255, 255, 255),
+		WithStdoutTTY(true),
+	)
+
+	if bg, err := f.Background(); err != nil || !bg.IsLight() {
+		t.Fatalf("Background() = %v, %v, expected light", bg, err)
+	}
+
+	cs := f.ColorScheme().Clone(
+		colorscheme.WithThemes(
+			colorscheme.Theme{"title": "black"},
+			colorscheme.Theme{"title": "white"},
+		),
+	)
+
+	want := "\x1b[0;30mtitle\x1b[0m"
+	if got := cs.ThemeFunc("title")("title"); got != want {
+		t.Fatalf("ThemeFunc()() = %q, expected %q", got, want)
+	}
+}
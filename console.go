@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/heaths/go-console/pkg/ansi"
 	"github.com/heaths/go-console/pkg/colorscheme"
 	"golang.org/x/term"
 )
@@ -24,16 +26,56 @@ type Console interface {
 	io.Writer
 
 	ColorScheme() *colorscheme.ColorScheme
+	Background() (Background, error)
 	Reset()
 
 	StartProgress(label string, opts ...ProgressOption)
 	StopProgress()
 
+	StartPager() error
+	StopPager() error
+
+	StartRecording(w io.Writer) error
+	StopRecording() error
+
 	ClearLine()
 	ClearLines(rows int)
+	ClearLinesDown(rows int)
+	ClearToEndOfLine()
+	ClearToStartOfLine()
+	EraseCharacters(columns int)
 	ClearScreen()
+	ClearToEndOfScreen()
+	ClearToStartOfScreen()
+	ClearScrollback()
 	StartAlternativeScreenBuffer()
 	StopAlternativeScreenBuffer()
+	HideCursor()
+	ShowCursor()
+	SetTitle(title string)
+
+	EnableRawMode() error
+	DisableRawMode() error
+	ReadEvent() (Event, error)
+	ReadLine(prompt string, opts ...LineOption) (string, error)
+	Pick(prompt string, items []string, opts ...PickOption) ([]int, error)
+	Form(v interface{}, opts ...FormOption) error
+	Edit(template string, opts ...EditOption) (string, error)
+	Run(cmd *exec.Cmd, opts ...RunOption) error
+	EnableMouse(tracking MouseTracking)
+	DisableMouse()
+	EnableBracketedPaste()
+	DisableBracketedPaste()
+	EnableFocusReporting()
+	DisableFocusReporting()
+	EnableKeyboardEnhancement(flags KeyboardEnhancement) bool
+	DisableKeyboardEnhancement()
+	Restore()
+	Guard() *Guard
+
+	BeginSynchronizedUpdate()
+	EndSynchronizedUpdate()
+	SynchronizedUpdate(fn func())
 
 	MoveCursor(rows, columns int)
 	CursorUp(rows int)
@@ -41,6 +83,15 @@ type Console interface {
 	CursorForward(columns int)
 	CursorBack(columns int)
 	CursorColumn(column int)
+
+	SetScrollRegion(top, bottom int)
+	ResetScrollRegion()
+	ScrollUp(rows int)
+	ScrollDown(rows int)
+	InsertLines(rows int)
+	DeleteLines(rows int)
+	InsertCharacters(columns int)
+	DeleteCharacters(columns int)
 }
 
 type con struct {
@@ -52,35 +103,198 @@ type con struct {
 	stderrOverride *bool
 	stdinOverride  *bool
 
-	sizeOverride *struct {
-		Width  int
-		Height int
-	}
+	stripEscapes bool
+
+	sizeOverride *size
+	sizeLock     sync.Mutex
+
+	// Terminal type e.g., TERM.
+	term string
+
+	sessionResize <-chan WindowSize
+	resized       chan struct{}
 
 	cs *colorscheme.ColorScheme
 
+	background     *Background
+	backgroundOnce sync.Once
+
+	input     *inputReader
+	inputLock sync.Mutex
+	queryLock sync.Mutex
+
+	modes     modes
+	modesLock sync.Mutex
+
+	frame      *frameWriter
+	frameDepth int
+	frameLock  sync.Mutex
+
+	// Whether synchronized output is supported once queried.
+	synchronized     int32
+	synchronizedOnce sync.Once
+
+	pager     *pagerWriter
+	fakePager *bool
+
+	fakeEditor func(content string) (string, error)
+
+	// How long a FakeConsole waits for expected output.
+	fakeTimeout time.Duration
+
+	recording  *recording
+	recordLock sync.Mutex
+
 	progress        *spinner.Spinner
+	progressWriter  *frameWriter
 	progressEnabled bool
 	progressLock    sync.Mutex
 	progressMin     <-chan time.Time
 }
 
+// Option configures a Console created by New or a FakeConsole created by Fake.
+type Option func(*con)
+
+// System gets a Console for the process' standard streams. It is the same as
+// New without options.
 func System() Console {
+	return New()
+}
+
+// New gets a Console for the process' standard streams or any other streams
+// passed as options e.g., for a network connection or to tee output.
+// Whether streams are terminals and the size of the terminal are detected
+// only for an *os.File unless overridden by options. Escape sequences written
+// to Stdout or Stderr are removed if not a terminal; see WithStripEscapes.
+func New(opts ...Option) Console {
+	c := newCon(os.Stdout, os.Stderr, os.Stdin)
+	c.stripEscapes = true
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.stripEscapes {
+		if !c.IsStdoutTTY() {
+			c.stdout = ansi.NewStripWriter(c.stdout)
+		}
+		if !c.IsStderrTTY() {
+			c.stderr = ansi.NewStripWriter(c.stderr)
+		}
+	}
+
+	if c.sessionResize != nil {
+		go c.watchResize(c.sessionResize)
+	}
+
+	return c
+}
+
+// WithStdout sets the Writer for Stdout. For a FakeConsole, if not a
+// *bytes.Buffer, output is also written to a buffer returned by Buffers.
+func WithStdout(stdout io.Writer) Option {
+	return func(c *con) {
+		c.stdout = stdout
+	}
+}
+
+// WithStdoutTTY overrides whether Stdout is a terminal.
+func WithStdoutTTY(tty bool) Option {
+	return func(c *con) {
+		c.stdoutOverride = &tty
+	}
+}
+
+// WithStderr sets the Writer for Stderr. For a FakeConsole, if not a
+// *bytes.Buffer, output is also written to a buffer returned by Buffers.
+func WithStderr(stderr io.Writer) Option {
+	return func(c *con) {
+		c.stderr = stderr
+	}
+}
+
+// WithStderrTTY overrides whether Stderr is a terminal.
+func WithStderrTTY(tty bool) Option {
+	return func(c *con) {
+		c.stderrOverride = &tty
+	}
+}
+
+// WithStdin sets the Reader for Stdin. For a FakeConsole, if not a
+// *bytes.Buffer, input is read from it when the console first reads input.
+func WithStdin(stdin io.Reader) Option {
+	return func(c *con) {
+		c.stdin = stdin
+	}
+}
+
+// WithStdinTTY overrides whether Stdin is a terminal. Raw mode is only
+// tracked and not set for a terminal that is not an *os.File.
+func WithStdinTTY(tty bool) Option {
+	return func(c *con) {
+		c.stdinOverride = &tty
+	}
+}
+
+// WithStripEscapes sets whether escape sequences written to Stdout or Stderr
+// of a Console created by New are removed if not a terminal e.g., when
+// redirected to a file or log collector. This includes output from commands
+// attached by Run. The default is true. Restore writes any incomplete UTF-8
+// sequence held at the end of output.
+func WithStripEscapes(strip bool) Option {
+	return func(c *con) {
+		c.stripEscapes = strip
+	}
+}
+
+// WithSize overrides the size of the terminal.
+func WithSize(width, height int) Option {
+	checkSize(width, height)
+	return func(c *con) {
+		c.sizeOverride = &size{
+			Width:  width,
+			Height: height,
+		}
+	}
+}
+
+type size struct {
+	Width  int
+	Height int
+}
+
+func checkSize(width, height int) {
+	if width < 0 {
+		panic("width cannot be less than 0")
+	}
+	if height < 0 {
+		panic("height cannot be less than 0")
+	}
+}
+
+func newCon(stdout, stderr io.Writer, stdin io.Reader) *con {
 	c := &con{
-		stdout: os.Stdout,
-		stderr: os.Stderr,
-		stdin:  os.Stdin,
+		stdout: stdout,
+		stderr: stderr,
+		stdin:  stdin,
+
+		term:    os.Getenv("TERM"),
+		resized: make(chan struct{}, 1),
 
 		progressEnabled: true,
 	}
 
-	c.cs = colorscheme.New(colorscheme.WithTTY(c.IsStdoutTTY))
+	c.cs = colorscheme.New(
+		colorscheme.WithTTY(c.supportsColor),
+		colorscheme.WithLightBackground(c.isLightBackground),
+	)
 
 	return c
 }
 
+// Stdout gets the console output. Writes are buffered during a synchronized
+// update.
 func (c *con) Stdout() io.Writer {
-	return c.stdout
+	return c.out()
 }
 
 func (c *con) IsStdoutTTY() bool {
@@ -96,6 +310,10 @@ func (c *con) IsStdoutTTY() bool {
 }
 
 func (c *con) Stderr() io.Writer {
+	if c.isRecording() {
+		return c.recorded(c.stderr)
+	}
+
 	return c.stderr
 }
 
@@ -111,7 +329,16 @@ func (c *con) IsStderrTTY() bool {
 	return false
 }
 
+// Stdin gets the console input. Once the console has read input to respond
+// to queries or decode events, this returns a reader over that buffered input.
 func (c *con) Stdin() io.Reader {
+	c.inputLock.Lock()
+	defer c.inputLock.Unlock()
+
+	if c.input != nil {
+		return c.input
+	}
+
 	return c.stdin
 }
 
@@ -128,8 +355,12 @@ func (c *con) IsStdinTTY() bool {
 }
 
 func (c *con) Size() (width, height int, err error) {
-	if c.sizeOverride != nil {
-		return c.sizeOverride.Width, c.sizeOverride.Height, nil
+	c.sizeLock.Lock()
+	sz := c.sizeOverride
+	c.sizeLock.Unlock()
+
+	if sz != nil {
+		return sz.Width, sz.Height, nil
 	}
 
 	if w, ok := c.stdin.(*os.File); ok {
@@ -141,7 +372,12 @@ func (c *con) Size() (width, height int, err error) {
 
 // Write implements Writer on the console and calls Write on Stdout.
 func (c *con) Write(p []byte) (n int, err error) {
-	return c.stdout.Write(p)
+	return c.out().Write(p)
+}
+
+// supportsColor gets whether Stdout is a terminal that supports color.
+func (c *con) supportsColor() bool {
+	return c.IsStdoutTTY() && c.term != "dumb"
 }
 
 // ColorScheme gets the color scheme for the console i.e., Stdout.
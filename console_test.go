@@ -1,8 +1,12 @@
 package console
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,3 +45,123 @@ func testIsTTY(s string, t *testing.T) {
 		t.Fatalf("Is%sTTY() = true, expected false", s)
 	}
 }
+
+func TestNew(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	stdin := strings.NewReader("input\n")
+
+	c := New(
+		WithStdout(stdout),
+		WithStdoutTTY(true),
+		WithStderr(stderr),
+		WithStdin(stdin),
+		WithStdinTTY(true),
+		WithSize(100, 30),
+	)
+
+	if !c.IsStdoutTTY() {
+		t.Fatalf("IsStdoutTTY() = false, expected true")
+	}
+	if c.IsStderrTTY() {
+		t.Fatalf("IsStderrTTY() = true, expected false")
+	}
+	if !c.IsStdinTTY() {
+		t.Fatalf("IsStdinTTY() = false, expected true")
+	}
+	if width, height, err := c.Size(); err != nil || width != 100 || height != 30 {
+		t.Fatalf("Size() = %d, %d, %v, expected 100, 30, nil", width, height, err)
+	}
+
+	fmt.Fprint(c, c.ColorScheme().Red("out"))
+	fmt.Fprint(c.Stderr(), "err")
+
+	if got, expected := stdout.String(), "\x1b[0;31mout\x1b[0m"; got != expected {
+		t.Fatalf("Stdout() wrote %q, expected %q", got, expected)
+	}
+	if got, expected := stderr.String(), "err"; got != expected {
+		t.Fatalf("Stderr() wrote %q, expected %q", got, expected)
+	}
+
+	b, err := io.ReadAll(c.Stdin())
+	if err != nil || string(b) != "input\n" {
+		t.Fatalf("Stdin() read %q, %v, expected %q", b, err, "input\n")
+	}
+}
+
+func TestNew_defaults(t *testing.T) {
+	c := New(WithStripEscapes(false)).(*con)
+	if c.stdout != os.Stdout || c.stderr != os.Stderr || c.stdin != os.Stdin {
+		t.Fatalf("New() did not default to standard streams")
+	}
+}
+
+func TestNew_fakeOptions(t *testing.T) {
+	opts := []Option{
+		WithStdout(&bytes.Buffer{}),
+		WithStdoutTTY(true),
+		WithSize(100, 30),
+	}
+
+	for name, c := range map[string]Console{"New": New(opts...), "Fake": Fake(opts...)} {
+		if !c.IsStdoutTTY() {
+			t.Fatalf("%s: IsStdoutTTY() = false, expected true", name)
+		}
+		if width, height, err := c.Size(); err != nil || width != 100 || height != 30 {
+			t.Fatalf("%s: Size() = %d, %d, %v, expected 100, 30, nil", name, width, height, err)
+		}
+	}
+}
+
+func TestNew_WithStripEscapes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		tty  bool
+		want string
+	}{
+		{name: "redirected", want: "hello"},
+		{name: "terminal", tty: true, want: "\x1b[31mhello\x1b[0m"},
+		{name: "disabled", opts: []Option{WithStripEscapes(false)}, want: "\x1b[31mhello\x1b[0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &strings.Builder{}
+			stderr := &strings.Builder{}
+			c := New(append([]Option{
+				WithStdout(stdout),
+				WithStdoutTTY(tt.tty),
+				WithStderr(stderr),
+				WithStderrTTY(tt.tty),
+			}, tt.opts...)...)
+
+			fmt.Fprint(c, "\x1b[31mhello\x1b[0m")
+			fmt.Fprint(c.Stderr(), "\x1b[31mhello\x1b[0m")
+
+			if got := stdout.String(); got != tt.want {
+				t.Fatalf("Stdout() wrote %q, expected %q", got, tt.want)
+			}
+			if got := stderr.String(); got != tt.want {
+				t.Fatalf("Stderr() wrote %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_WithStripEscapes_restore(t *testing.T) {
+	stdout := &strings.Builder{}
+	c := New(WithStdout(stdout), WithStdoutTTY(false))
+
+	fmt.Fprint(c, "\x1b[1mcaf\xc3")
+	if got := stdout.String(); got != "caf" {
+		t.Fatalf("Stdout() wrote %q, expected %q", got, "caf")
+	}
+
+	c.Restore()
+	if got := stdout.String(); got != "caf\xc3" {
+		t.Fatalf("Restore() wrote %q, expected %q", got, "caf\xc3")
+	}
+}
@@ -0,0 +1,168 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EditOption configures Edit.
+type EditOption func(*editOptions)
+
+type editOptions struct {
+	commentPrefix string
+	fileName      string
+}
+
+// WithCommentPrefix sets the prefix of lines removed after editing, which is
+// "#" by default. An empty prefix removes no lines.
+func WithCommentPrefix(prefix string) EditOption {
+	return func(o *editOptions) {
+		o.commentPrefix = prefix
+	}
+}
+
+// WithFileName sets the name of the temporary file to edit, which may help
+// the editor detect the file type e.g., "COMMIT_EDITMSG" or "description.md".
+func WithFileName(name string) EditOption {
+	return func(o *editOptions) {
+		o.fileName = name
+	}
+}
+
+// Edit opens the editor in $VISUAL or $EDITOR, or a platform default, on a
+// temporary file containing template and waits for it to exit. It returns the
+// edited content with comment lines removed and trailing space trimmed.
+//
+// The editor is connected to the terminal after leaving the alternative
+// screen buffer and restoring other modes, which are changed back after the
+// editor exits. Callers using the alternative screen buffer should redraw.
+func (c *con) Edit(template string, opts ...EditOption) (string, error) {
+	o := &editOptions{
+		commentPrefix: "#",
+		fileName:      "EDIT.txt",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	edit := c.edit
+	if c.fakeEditor != nil {
+		edit = func(template, _ string) (string, error) {
+			return c.fakeEditor(template)
+		}
+	}
+
+	content, err := edit(template, o.fileName)
+	if err != nil {
+		return "", err
+	}
+
+	if o.commentPrefix != "" {
+		var lines []string
+		for _, line := range strings.Split(content, "\n") {
+			if !strings.HasPrefix(line, o.commentPrefix) {
+				lines = append(lines, line)
+			}
+		}
+		content = strings.Join(lines, "\n")
+	}
+
+	return strings.TrimRight(content, " \t\r\n"), nil
+}
+
+func (c *con) edit(template, fileName string) (string, error) {
+	if !c.IsStdinTTY() || !c.IsStdoutTTY() {
+		return "", fmt.Errorf("cannot start editor: %w", errNotTTY)
+	}
+
+	dir, err := os.MkdirTemp("", "console-edit-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(fileName))
+	if err := os.WriteFile(path, []byte(template), 0600); err != nil {
+		return "", err
+	}
+
+	cmd := editorCmd(editorCommand(), path)
+	cmd.Stdin = c.stdin
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+
+	resume := c.suspend()
+	err = cmd.Run()
+	resume()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to run editor: %w", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// editorCmd gets a command to run editor, which may contain arguments and
+// quotes, on path.
+func editorCmd(editor, path string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		args := splitCommand(editor)
+		return exec.Command(args[0], append(args[1:], path)...)
+	}
+
+	// Let the shell parse quotes, escapes, and variables like git does.
+	return exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+}
+
+// splitCommand splits s into arguments separated by spaces outside of double
+// quotes, which are removed e.g., `"C:\Program Files\editor.exe" -w`.
+func splitCommand(s string) []string {
+	var args []string
+	var sb strings.Builder
+	quoted, started := false, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if started {
+				args = append(args, sb.String())
+				sb.Reset()
+				started = false
+			}
+		default:
+			sb.WriteRune(r)
+			started = true
+		}
+	}
+
+	if started {
+		args = append(args, sb.String())
+	}
+	return args
+}
+
+// editorCommand gets the editor command from $VISUAL, $EDITOR, or a default.
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "vi"
+}
@@ -0,0 +1,157 @@
+package console
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConsole_Edit(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		edited   string
+		opts     []EditOption
+		want     string
+	}{
+		{
+			name:     "strip comments",
+			template: "\n# Enter a description.\n",
+			edited:   "Fix the thing.\n\nIt was broken.\n# Enter a description.\n",
+			want:     "Fix the thing.\n\nIt was broken.",
+		},
+		{
+			name:     "comment prefix",
+			template: "<!-- Enter a description. -->\n",
+			edited:   "# Title\n<!-- Enter a description. -->\n",
+			opts:     []EditOption{WithCommentPrefix("<!--")},
+			want:     "# Title",
+		},
+		{
+			name:   "no comment prefix",
+			edited: "# Title\n\n",
+			opts:   []EditOption{WithCommentPrefix("")},
+			want:   "# Title",
+		},
+		{
+			name:     "unchanged",
+			template: "# Enter a description.\n",
+			edited:   "# Enter a description.\n",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			f := Fake(
+				WithEditor(func(content string) (string, error) {
+					got = content
+					return tt.edited, nil
+				}),
+			)
+
+			edited, err := f.Edit(tt.template, tt.opts...)
+			if err != nil {
+				t.Fatalf("Edit() error = %v", err)
+			}
+			if got != tt.template {
+				t.Fatalf("Edit() passed %q, expected %q", got, tt.template)
+			}
+			if edited != tt.want {
+				t.Fatalf("Edit() = %q, expected %q", edited, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_Edit_error(t *testing.T) {
+	errEditor := errors.New("editor failed")
+	f := Fake(
+		WithEditor(func(string) (string, error) {
+			return "", errEditor
+		}),
+	)
+
+	if _, err := f.Edit(""); !errors.Is(err, errEditor) {
+		t.Fatalf("Edit() error = %v, expected %v", err, errEditor)
+	}
+}
+
+func TestConsole_Edit_notTTY(t *testing.T) {
+	if _, err := Fake().Edit(""); !errors.Is(err, errNotTTY) {
+		t.Fatalf("Edit() error = %v, expected %v", err, errNotTTY)
+	}
+}
+
+func TestConsole_Edit_command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	// The path of the editor may contain spaces if quoted.
+	dir := filepath.Join(t.TempDir(), "my editor")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "editor")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"edited $(basename \"$1\")\" >> \"$1\"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("VISUAL", "'"+script+"'")
+	t.Setenv("EDITOR", "false")
+
+	f := Fake(
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	f.StartAlternativeScreenBuffer()
+	f.HideCursor()
+	if err := f.EnableRawMode(); err != nil {
+		t.Fatalf("EnableRawMode() error = %v", err)
+	}
+
+	stdout, _, _ := f.Buffers()
+	stdout.Reset()
+
+	got, err := f.Edit("# template\n", WithFileName("COMMIT_EDITMSG"))
+	if err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	if want := "edited COMMIT_EDITMSG"; got != want {
+		t.Fatalf("Edit() = %q, expected %q", got, want)
+	}
+
+	if want := "\x1b[?25h\x1b[?1049l\x1b[?1049h\x1b[?25l"; !strings.HasPrefix(stdout.String(), want) {
+		t.Fatalf("Edit() wrote %q, expected %q", stdout.String(), want)
+	}
+	if !f.isRaw() {
+		t.Fatal("Edit() did not enable raw mode again")
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{s: "notepad", want: []string{"notepad"}},
+		{s: "code  --wait", want: []string{"code", "--wait"}},
+		{s: `"C:\Program Files\Editor\editor.exe" -w`, want: []string{`C:\Program Files\Editor\editor.exe`, "-w"}},
+		{s: `editor ""`, want: []string{"editor", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := splitCommand(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitCommand() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package console
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/heaths/go-console/internal/text"
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Cell is a character on a Screen and its style.
+type Cell struct {
+	// Rune is the character in the cell, or 0 if the cell is covered by a
+	// wide character in the previous cell.
+	Rune  rune
+	Style ansi.Style
+}
+
+var blank = Cell{Rune: ' '}
+
+// Screen is a simple virtual terminal that interprets text and common control
+// sequences written to it. FakeConsole renders Stdout to a Screen so tests can
+// assert what a user would see.
+type Screen struct {
+	width, height int
+
+	main, alt [][]Cell
+	cells     [][]Cell
+
+	style          ansi.Style
+	row, col       int
+	savedRow       int
+	savedCol       int
+	savedStyle     ansi.Style
+	pendingWrap    bool
+	cursorHidden   bool
+	top, bottom    int
+	altScreen      bool
+	altSavedRow    int
+	altSavedCol    int
+	altSavedTop    int
+	altSavedBottom int
+
+	t ansi.Tokenizer
+}
+
+// NewScreen creates a new Screen with the given size.
+func NewScreen(width, height int) *Screen {
+	if width < 1 {
+		panic("width cannot be less than 1")
+	}
+	if height < 1 {
+		panic("height cannot be less than 1")
+	}
+
+	s := &Screen{
+		width:  width,
+		height: height,
+		main:   newCells(width, height),
+		bottom: height - 1,
+	}
+	s.cells = s.main
+
+	return s
+}
+
+func newCells(width, height int) [][]Cell {
+	cells := make([][]Cell, height)
+	for i := range cells {
+		cells[i] = newRow(width)
+	}
+
+	return cells
+}
+
+func newRow(width int) []Cell {
+	row := make([]Cell, width)
+	for i := range row {
+		row[i] = blank
+	}
+
+	return row
+}
+
+// Size gets the width and height of the Screen.
+func (s *Screen) Size() (width, height int) {
+	return s.width, s.height
+}
+
+// Cursor gets the 1-based row and column of the cursor.
+func (s *Screen) Cursor() (row, column int) {
+	return s.row + 1, s.col + 1
+}
+
+// CursorVisible returns whether the cursor is visible.
+func (s *Screen) CursorVisible() bool {
+	return !s.cursorHidden
+}
+
+// ScrollRegion gets the 1-based top and bottom rows of the scroll region.
+func (s *Screen) ScrollRegion() (top, bottom int) {
+	return s.top + 1, s.bottom + 1
+}
+
+// AlternativeScreenBuffer returns whether the alternative screen buffer is
+// active.
+func (s *Screen) AlternativeScreenBuffer() bool {
+	return s.altScreen
+}
+
+// Lines gets each row of the Screen with trailing spaces removed.
+func (s *Screen) Lines() []string {
+	lines := make([]string, s.height)
+	for i, row := range s.cells {
+		var sb strings.Builder
+		for _, c := range row {
+			if c.Rune != 0 {
+				sb.WriteRune(c.Rune)
+			}
+		}
+		lines[i] = strings.TrimRight(sb.String(), " ")
+	}
+
+	return lines
+}
+
+// Cell gets the character and its style at the 1-based row and column.
+func (s *Screen) Cell(row, column int) Cell {
+	if row < 1 || row > s.height || column < 1 || column > s.width {
+		panic("cell out of range")
+	}
+
+	return s.cells[row-1][column-1]
+}
+
+// String gets the rows of the Screen separated by newlines with trailing
+// spaces and empty rows removed.
+func (s *Screen) String() string {
+	return strings.TrimRight(strings.Join(s.Lines(), "\n"), "\n")
+}
+
+// Write implements io.Writer and interprets text and control sequences.
+func (s *Screen) Write(p []byte) (n int, err error) {
+	s.t.Tokenize(p, s.token)
+
+	return len(p), nil
+}
+
+func (s *Screen) token(tok ansi.Token) {
+	switch tok.Kind {
+	case ansi.TokenText:
+		for b := tok.Raw; len(b) > 0; {
+			r, n := utf8.DecodeRune(b)
+			s.print(r)
+			b = b[n:]
+		}
+	case ansi.TokenControl:
+		s.control(tok.Final)
+	case ansi.TokenEscape:
+		if len(tok.Intermediates) == 0 {
+			s.escape(tok.Final)
+		}
+	case ansi.TokenCSI:
+		s.csi(tok)
+	}
+}
+
+func (s *Screen) control(b byte) {
+	switch b {
+	case '\b':
+		if s.col > 0 {
+			s.col--
+		}
+		s.pendingWrap = false
+	case '\t':
+		s.col = (s.col/8 + 1) * 8
+		if s.col >= s.width {
+			s.col = s.width - 1
+		}
+		s.pendingWrap = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\r':
+		s.col = 0
+		s.pendingWrap = false
+	}
+}
+
+func (s *Screen) escape(b byte) {
+	switch b {
+	case '7':
+		s.saveCursor()
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.col = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseIndex()
+	case 'c':
+		// Keep the state of the tokenizer, which is still running.
+		t := s.t
+		*s = *NewScreen(s.width, s.height)
+		s.t = t
+	}
+}
+
+func (s *Screen) print(r rune) {
+	w := text.RuneWidth(r)
+	if w == 0 {
+		// Combining marks are not supported.
+		return
+	}
+	if w > s.width {
+		// Wide characters cannot fit on a screen narrower than them.
+		return
+	}
+
+	if s.pendingWrap {
+		s.col = 0
+		s.lineFeed()
+	}
+
+	if w > 1 && s.col+w > s.width {
+		// Wrap wide characters that would not fit.
+		s.clearCells(s.row, s.col, s.width)
+		s.col = 0
+		s.lineFeed()
+	}
+
+	s.cells[s.row][s.col] = Cell{Rune: r, Style: s.style}
+	for i := 1; i < w; i++ {
+		// Mark cells covered by wide characters.
+		s.cells[s.row][s.col+i] = Cell{Style: s.style}
+	}
+
+	if s.col+w >= s.width {
+		s.col = s.width - 1
+		s.pendingWrap = true
+	} else {
+		s.col += w
+	}
+}
+
+func (s *Screen) lineFeed() {
+	s.pendingWrap = false
+	if s.row == s.bottom {
+		s.scrollUp(1)
+	} else if s.row < s.height-1 {
+		s.row++
+	}
+}
+
+func (s *Screen) reverseIndex() {
+	s.pendingWrap = false
+	if s.row == s.top {
+		s.scrollDown(1)
+	} else if s.row > 0 {
+		s.row--
+	}
+}
+
+// scrollUp moves rows in the scroll region up, inserting blank rows at the
+// bottom.
+func (s *Screen) scrollUp(n int) {
+	s.deleteRows(s.top, n)
+}
+
+// scrollDown moves rows in the scroll region down, inserting blank rows at
+// the top.
+func (s *Screen) scrollDown(n int) {
+	s.insertRows(s.top, n)
+}
+
+// insertRows inserts n blank rows at row within the scroll region.
+func (s *Screen) insertRows(row, n int) {
+	if n > s.bottom-row+1 {
+		n = s.bottom - row + 1
+	}
+
+	copy(s.cells[row+n:s.bottom+1], s.cells[row:s.bottom+1-n])
+	for i := row; i < row+n; i++ {
+		s.cells[i] = newRow(s.width)
+	}
+}
+
+// deleteRows deletes n rows at row within the scroll region.
+func (s *Screen) deleteRows(row, n int) {
+	if n > s.bottom-row+1 {
+		n = s.bottom - row + 1
+	}
+
+	copy(s.cells[row:s.bottom+1-n], s.cells[row+n:s.bottom+1])
+	for i := s.bottom + 1 - n; i <= s.bottom; i++ {
+		s.cells[i] = newRow(s.width)
+	}
+}
+
+func (s *Screen) inScrollRegion() bool {
+	return s.row >= s.top && s.row <= s.bottom
+}
+
+func (s *Screen) saveCursor() {
+	s.savedRow, s.savedCol = s.row, s.col
+	s.savedStyle = s.style
+}
+
+func (s *Screen) restoreCursor() {
+	s.row, s.col = s.savedRow, s.savedCol
+	s.style = s.savedStyle
+	s.pendingWrap = false
+}
+
+func (s *Screen) clearCells(row, from, to int) {
+	for i := from; i < to; i++ {
+		s.cells[row][i] = blank
+	}
+}
+
+func (s *Screen) clearRows(from, to int) {
+	for i := from; i < to; i++ {
+		s.cells[i] = newRow(s.width)
+	}
+}
+
+func (s *Screen) csi(tok ansi.Token) {
+	// Ignore sequences with intermediates or private markers other than "?".
+	if len(tok.Intermediates) > 0 || tok.Private != 0 && tok.Private != '?' {
+		return
+	}
+
+	final := tok.Final
+	if tok.Private == '?' {
+		if final == 'h' || final == 'l' {
+			s.privateMode(tok, final == 'h')
+		}
+		return
+	}
+
+	if final == 'm' {
+		s.style = s.style.SGR(string(tok.Params))
+		return
+	}
+
+	arg := func(i, def int) int {
+		if v := tok.Param(i, 0); v > 0 {
+			return v
+		}
+		return def
+	}
+
+	switch final {
+	case 'A':
+		top := 0
+		if s.inScrollRegion() {
+			top = s.top
+		}
+		s.row = clamp(s.row-arg(0, 1), top, s.height-1)
+	case 'B':
+		bottom := s.height - 1
+		if s.inScrollRegion() {
+			bottom = s.bottom
+		}
+		s.row = clamp(s.row+arg(0, 1), 0, bottom)
+	case 'C':
+		s.col = clamp(s.col+arg(0, 1), 0, s.width-1)
+	case 'D':
+		s.col = clamp(s.col-arg(0, 1), 0, s.width-1)
+	case 'G':
+		s.col = clamp(arg(0, 1)-1, 0, s.width-1)
+	case 'H', 'f':
+		s.row = clamp(arg(0, 1)-1, 0, s.height-1)
+		s.col = clamp(arg(1, 1)-1, 0, s.width-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.clearCells(s.row, s.col, s.width)
+			s.clearRows(s.row+1, s.height)
+		case 1:
+			s.clearRows(0, s.row)
+			s.clearCells(s.row, 0, s.col+1)
+		case 2:
+			s.clearRows(0, s.height)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.clearCells(s.row, s.col, s.width)
+		case 1:
+			s.clearCells(s.row, 0, s.col+1)
+		case 2:
+			s.clearCells(s.row, 0, s.width)
+		}
+	case 'L':
+		if s.inScrollRegion() {
+			s.insertRows(s.row, arg(0, 1))
+			s.col = 0
+		}
+	case 'M':
+		if s.inScrollRegion() {
+			s.deleteRows(s.row, arg(0, 1))
+			s.col = 0
+		}
+	case '@':
+		n := clamp(arg(0, 1), 0, s.width-s.col)
+		row := s.cells[s.row]
+		copy(row[s.col+n:], row[s.col:s.width-n])
+		s.clearCells(s.row, s.col, s.col+n)
+	case 'P':
+		n := clamp(arg(0, 1), 0, s.width-s.col)
+		row := s.cells[s.row]
+		copy(row[s.col:], row[s.col+n:])
+		s.clearCells(s.row, s.width-n, s.width)
+	case 'X':
+		n := clamp(arg(0, 1), 0, s.width-s.col)
+		s.clearCells(s.row, s.col, s.col+n)
+	case 'S':
+		s.scrollUp(arg(0, 1))
+	case 'T':
+		s.scrollDown(arg(0, 1))
+	case 'r':
+		top, bottom := arg(0, 1)-1, arg(1, s.height)-1
+		if bottom >= s.height {
+			bottom = s.height - 1
+		}
+		if top < bottom {
+			s.top, s.bottom = top, bottom
+			s.row, s.col = 0, 0
+		}
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	default:
+		return
+	}
+
+	s.pendingWrap = false
+}
+
+func (s *Screen) privateMode(tok ansi.Token, set bool) {
+	for i := 0; i < tok.NumParams(); i++ {
+		mode := tok.Param(i, 0)
+		switch mode {
+		case 25:
+			s.cursorHidden = !set
+		case 1049:
+			if set == s.altScreen {
+				continue
+			}
+
+			if set {
+				s.altSavedRow, s.altSavedCol = s.row, s.col
+				s.altSavedTop, s.altSavedBottom = s.top, s.bottom
+				s.alt = newCells(s.width, s.height)
+				s.cells = s.alt
+			} else {
+				s.row, s.col = s.altSavedRow, s.altSavedCol
+				s.top, s.bottom = s.altSavedTop, s.altSavedBottom
+				s.cells = s.main
+			}
+			s.altScreen = set
+			s.pendingWrap = false
+		}
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
@@ -0,0 +1,252 @@
+package console
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+func TestScreen_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		width   int
+		height  int
+		input   string
+		want    []string
+		wantRow int
+		wantCol int
+	}{
+		{
+			name:    "text",
+			input:   "hello\r\nworld",
+			want:    []string{"hello", "world", ""},
+			wantRow: 2,
+			wantCol: 6,
+		},
+		{
+			name:    "wrap",
+			width:   4,
+			input:   "abcdef",
+			want:    []string{"abcd", "ef", ""},
+			wantRow: 2,
+			wantCol: 3,
+		},
+		{
+			name:    "pending wrap",
+			width:   4,
+			input:   "abcd",
+			want:    []string{"abcd", "", ""},
+			wantRow: 1,
+			wantCol: 4,
+		},
+		{
+			name:    "scroll",
+			input:   "1\r\n2\r\n3\r\n4",
+			want:    []string{"2", "3", "4"},
+			wantRow: 3,
+			wantCol: 2,
+		},
+		{
+			name:    "unicode",
+			input:   "héllo",
+			want:    []string{"héllo", "", ""},
+			wantRow: 1,
+			wantCol: 6,
+		},
+		{
+			name:    "wide",
+			width:   5,
+			input:   "世界世",
+			want:    []string{"世界", "世", ""},
+			wantRow: 2,
+			wantCol: 3,
+		},
+		{
+			name:    "wide too narrow",
+			width:   1,
+			input:   "a世b",
+			want:    []string{"a", "b", ""},
+			wantRow: 2,
+			wantCol: 1,
+		},
+		{
+			name:    "move cursor",
+			input:   "\x1b[2;3Hx\x1b[Ay\x1b[2Dz",
+			want:    []string{"  zy", "  x", ""},
+			wantRow: 1,
+			wantCol: 4,
+		},
+		{
+			name:    "ignore SGR and OSC",
+			input:   "\x1b[0;31mred\x1b[0m\x1b]0;title\x1b\\\x1b]0;title\a!",
+			want:    []string{"red!", "", ""},
+			wantRow: 1,
+			wantCol: 5,
+		},
+		{
+			name:    "clear line",
+			input:   "abc\x1b[2K",
+			want:    []string{"", "", ""},
+			wantRow: 1,
+			wantCol: 4,
+		},
+		{
+			name:    "clear screen",
+			input:   "abc\r\ndef\x1b[2J\x1b[1;1H",
+			want:    []string{"", "", ""},
+			wantRow: 1,
+			wantCol: 1,
+		},
+		{
+			name:    "scroll region",
+			input:   "1\r\n2\r\n3\x1b[1;2r\x1b[2;1H\n4",
+			want:    []string{"2", "4", "3"},
+			wantRow: 2,
+			wantCol: 2,
+		},
+		{
+			name:    "scroll up and down",
+			input:   "1\r\n2\r\n3\x1b[S\x1b[2T",
+			want:    []string{"", "", "2"},
+			wantRow: 3,
+			wantCol: 2,
+		},
+		{
+			name:    "insert and delete lines",
+			input:   "1\r\n2\r\n3\x1b[2;1H\x1b[L\x1b[3;1H\x1b[M",
+			want:    []string{"1", "", ""},
+			wantRow: 3,
+			wantCol: 1,
+		},
+		{
+			name:    "insert and delete characters",
+			input:   "abcdef\x1b[1;2H\x1b[2@\x1b[1;6H\x1b[P",
+			want:    []string{"a  bcef", "", ""},
+			wantRow: 1,
+			wantCol: 6,
+		},
+		{
+			name:    "alternative screen buffer",
+			input:   "main\x1b[?1049halt\x1b[?1049l",
+			want:    []string{"main", "", ""},
+			wantRow: 1,
+			wantCol: 5,
+		},
+		{
+			name:    "ignore private markers",
+			input:   "a\x1b[>1u\x1b[?2026$pb",
+			want:    []string{"ab", "", ""},
+			wantRow: 1,
+			wantCol: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := tt.width, tt.height
+			if width == 0 {
+				width = 10
+			}
+			if height == 0 {
+				height = 3
+			}
+
+			s := NewScreen(width, height)
+			if _, err := s.Write([]byte(tt.input)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			if got := s.Lines(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Lines() = %q, expected %q", got, tt.want)
+			}
+
+			if row, col := s.Cursor(); row != tt.wantRow || col != tt.wantCol {
+				t.Fatalf("Cursor() = %d, %d, expected %d, %d", row, col, tt.wantRow, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestScreen_Write_split(t *testing.T) {
+	input := "\x1b[0;31mhé\x1b]0;title\x1b\\llo\x1b[0m"
+	s := NewScreen(10, 1)
+	for i := 0; i < len(input); i++ {
+		// nolint:errcheck
+		s.Write([]byte{input[i]})
+	}
+
+	if got := s.String(); got != "héllo" {
+		t.Fatalf("String() = %q, expected %q", got, "héllo")
+	}
+}
+
+func TestFakeConsole_Screen(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+		WithSize(20, 5),
+	)
+
+	f.StartAlternativeScreenBuffer()
+	f.SetScrollRegion(2, 4)
+	for i := 1; i <= 5; i++ {
+		f.MoveCursor(4, 1)
+		f.ScrollUp(1)
+		f.MoveCursor(4, 1)
+		f.Write([]byte(strings.Repeat("*", i))) // nolint:errcheck
+	}
+	f.MoveCursor(2, 1)
+	f.InsertLines(1)
+	f.MoveCursor(3, 1)
+	f.DeleteCharacters(2)
+	f.InsertCharacters(1)
+
+	s := f.Screen()
+	if !s.AlternativeScreenBuffer() {
+		t.Fatal("AlternativeScreenBuffer() = false, expected true")
+	}
+
+	if top, bottom := s.ScrollRegion(); top != 2 || bottom != 4 {
+		t.Fatalf("ScrollRegion() = %d, %d, expected 2, 4", top, bottom)
+	}
+
+	want := []string{"", "", " *", "****", ""}
+	if got := s.Lines(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Lines() = %q, expected %q", got, want)
+	}
+
+	f.Restore()
+	if top, bottom := f.Screen().ScrollRegion(); top != 1 || bottom != 5 {
+		t.Fatalf("ScrollRegion() = %d, %d, expected 1, 5 after Restore()", top, bottom)
+	}
+}
+
+func TestScreen_Cell(t *testing.T) {
+	s := NewScreen(10, 2)
+
+	// Saving and restoring the cursor also restores the style.
+	// nolint:errcheck
+	s.Write([]byte("\x1b[1;31ma\x1b[7m世\x1b7\x1b[0mb\x1b8c"))
+
+	bold := ansi.Style{Bold: true, Foreground: ansi.IndexedColor(1)}
+	inverse := bold
+	inverse.Inverse = true
+
+	tests := []struct {
+		column int
+		want   Cell
+	}{
+		{column: 1, want: Cell{Rune: 'a', Style: bold}},
+		{column: 2, want: Cell{Rune: '世', Style: inverse}},
+		{column: 3, want: Cell{Style: inverse}},
+		{column: 4, want: Cell{Rune: 'c', Style: inverse}},
+		{column: 5, want: Cell{Rune: ' '}},
+	}
+
+	for _, tt := range tests {
+		if got := s.Cell(1, tt.column); got != tt.want {
+			t.Fatalf("Cell(1, %d) = %+v, expected %+v", tt.column, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,563 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// escapeTimeout is how long to wait for the rest of an escape sequence before
+// treating ESC as a key press.
+const escapeTimeout = 50 * time.Millisecond
+
+// Event is an input event read using ReadEvent e.g., a KeyEvent or MouseEvent.
+type Event interface {
+	isEvent()
+}
+
+// Key is a key pressed on the keyboard.
+type Key int
+
+const (
+	// KeyRune is a key that produces the rune in KeyEvent.Rune.
+	KeyRune Key = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+var keyNames = [...]string{
+	"Rune", "Enter", "Tab", "Backspace", "Escape",
+	"Up", "Down", "Right", "Left", "Home", "End", "PageUp", "PageDown", "Insert", "Delete",
+	"F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12",
+}
+
+func (k Key) String() string {
+	if k >= 0 && int(k) < len(keyNames) {
+		return keyNames[k]
+	}
+
+	return "Key(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Modifiers are keys held while pressing another key or mouse button.
+type Modifiers int
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModAlt
+	ModCtrl
+	ModMeta
+
+	modifiersMask = ModShift | ModAlt | ModCtrl | ModMeta
+)
+
+func (m Modifiers) String() string {
+	var names []string
+	for i, name := range []string{"Shift", "Alt", "Ctrl", "Meta"} {
+		if m&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, "+")
+}
+
+// KeyAction is whether a key was pressed, repeated, or released.
+// Only KeyPress is reported unless keyboard enhancements are enabled.
+type KeyAction int
+
+const (
+	KeyPress KeyAction = iota
+	KeyRepeat
+	KeyRelease
+)
+
+// KeyEvent is a key pressed on the keyboard.
+type KeyEvent struct {
+	Key       Key
+	Rune      rune
+	Modifiers Modifiers
+	Action    KeyAction
+}
+
+func (KeyEvent) isEvent() {}
+
+func (e KeyEvent) String() string {
+	s := e.Key.String()
+	if e.Key == KeyRune {
+		s = string(e.Rune)
+	}
+
+	if e.Modifiers != 0 {
+		s = e.Modifiers.String() + "+" + s
+	}
+
+	return s
+}
+
+// PasteEvent is text pasted into the terminal. See EnableBracketedPaste.
+type PasteEvent struct {
+	// Text is the pasted text with line endings normalized to "\n".
+	Text string
+}
+
+func (PasteEvent) isEvent() {}
+
+// FocusEvent is sent when the terminal gains focus.
+// See EnableFocusReporting.
+type FocusEvent struct{}
+
+func (FocusEvent) isEvent() {}
+
+// BlurEvent is sent when the terminal loses focus.
+// See EnableFocusReporting.
+type BlurEvent struct{}
+
+func (BlurEvent) isEvent() {}
+
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+
+	lineEndings = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+// ReadEvent reads the next input event from Stdin, blocking until one is
+// available. Stdin should typically be in raw mode; see EnableRawMode.
+// Other methods that query the terminal may be called while waiting.
+// A ResizeEvent is returned when a session is resized; see WithSession.
+// Unrecognized escape sequences are ignored.
+func (c *con) ReadEvent() (Event, error) {
+	c.inputLock.Lock()
+	defer c.inputLock.Unlock()
+
+	in := c.reader()
+	for {
+		in.waitForQuery()
+		if len(in.buf) == 0 {
+			if err := in.fillOrResize(-1, c.resized); err == errResized {
+				width, height, _ := c.Size()
+				return ResizeEvent{Width: width, Height: height}, nil
+			} else if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		ev, n := decodeEvent(in.buf, false)
+		if n == 0 {
+			// Wait briefly for the rest of an incomplete sequence, or until
+			// the end of pasted text.
+			timeout := escapeTimeout
+			if bytes.HasPrefix(in.buf, pasteStart) {
+				timeout = -1
+			}
+
+			if err := in.fill(timeout); err == nil {
+				continue
+			}
+
+			in.waitForQuery()
+			if len(in.buf) == 0 {
+				continue
+			}
+			ev, n = decodeEvent(in.buf, true)
+		}
+
+		in.buf = in.buf[n:]
+		if ev != nil {
+			return ev, nil
+		}
+	}
+}
+
+// decodeEvent decodes the first event in b and returns the number of bytes
+// decoded. If b contains an incomplete sequence, 0 is returned unless final,
+// in which case as much is decoded as possible. A nil Event with n > 0 is an
+// unrecognized sequence that should be skipped.
+func decodeEvent(b []byte, final bool) (Event, int) {
+	if b[0] != 0x1b {
+		return decodeKey(b, final)
+	}
+
+	if len(b) == 1 {
+		if final {
+			return KeyEvent{Key: KeyEscape}, 1
+		}
+		return nil, 0
+	}
+
+	switch b[1] {
+	case ']', 'P', '_', '^':
+		// Discard strings e.g., late responses to queries.
+		if n := stringLength(b); n > 0 {
+			return nil, n
+		} else if !final {
+			return nil, 0
+		}
+	}
+
+	var ev Event
+	var n int
+	switch b[1] {
+	case '[':
+		ev, n = decodeCSI(b, final)
+	case 'O':
+		ev, n = decodeSS3(b)
+	case 0x1b:
+		return KeyEvent{Key: KeyEscape}, 1
+	default:
+		// Alt modifies the next key.
+		ev, n = decodeKey(b[1:], final)
+		if key, ok := ev.(KeyEvent); ok {
+			key.Modifiers |= ModAlt
+			return key, n + 1
+		}
+		if n == 0 && !final {
+			return nil, 0
+		}
+		return KeyEvent{Key: KeyEscape}, 1
+	}
+
+	if n == 0 && !final {
+		return nil, 0
+	}
+
+	if n <= 0 {
+		// Malformed or incomplete, so treat ESC as a key press.
+		return KeyEvent{Key: KeyEscape}, 1
+	}
+
+	return ev, n
+}
+
+// stringLength gets the length of an OSC, DCS, APC, or PM string terminated by
+// BEL or ST, or 0 if incomplete. Any other escape sequence also terminates the
+// string and is not included in its length.
+func stringLength(b []byte) int {
+	for i := 2; i < len(b); i++ {
+		switch b[i] {
+		case 0x07:
+			return i + 1
+		case 0x1b:
+			if i+1 == len(b) {
+				return 0
+			}
+			if b[i+1] == '\\' {
+				return i + 2
+			}
+			return i
+		}
+	}
+
+	return 0
+}
+
+// decodeKey decodes a single key that is not part of an escape sequence.
+func decodeKey(b []byte, final bool) (Event, int) {
+	switch c := b[0]; {
+	case c == '\r' || c == '\n':
+		return KeyEvent{Key: KeyEnter}, 1
+	case c == '\t':
+		return KeyEvent{Key: KeyTab}, 1
+	case c == 0x7f || c == 0x08:
+		return KeyEvent{Key: KeyBackspace}, 1
+	case c == 0x1b:
+		return KeyEvent{Key: KeyEscape}, 1
+	case c == 0:
+		return KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl}, 1
+	case c < 0x1b:
+		return KeyEvent{Key: KeyRune, Rune: rune('a' + c - 1), Modifiers: ModCtrl}, 1
+	case c < 0x20:
+		return KeyEvent{Key: KeyRune, Rune: rune('\\' + c - 0x1c), Modifiers: ModCtrl}, 1
+	case c < utf8.RuneSelf:
+		return KeyEvent{Key: KeyRune, Rune: rune(c)}, 1
+	}
+
+	if !utf8.FullRune(b) && !final {
+		return nil, 0
+	}
+
+	r, n := utf8.DecodeRune(b)
+	return KeyEvent{Key: KeyRune, Rune: r}, n
+}
+
+// decodeCSI decodes a control sequence introducer (CSI) sequence starting with
+// ESC [. It returns 0 if incomplete or -1 if malformed.
+func decodeCSI(b []byte, final bool) (Event, int) {
+	i := 2
+	for ; i < len(b); i++ {
+		c := b[i]
+		if c >= 0x40 && c <= 0x7e {
+			break
+		}
+		if c < 0x20 || c > 0x3f {
+			return nil, -1
+		}
+	}
+
+	if i == len(b) {
+		return nil, 0
+	}
+
+	params, cmd, n := string(b[2:i]), b[i], i+1
+	if strings.HasPrefix(params, "<") && (cmd == 'M' || cmd == 'm') {
+		if ev, ok := decodeSGRMouse(params[1:], cmd); ok {
+			return ev, n
+		}
+		return nil, n
+	}
+
+	if params == "200" && cmd == '~' {
+		end := bytes.Index(b[n:], pasteEnd)
+		if end < 0 {
+			if !final {
+				return nil, 0
+			}
+			return PasteEvent{Text: lineEndings.Replace(string(b[n:]))}, len(b)
+		}
+
+		return PasteEvent{Text: lineEndings.Replace(string(b[n : n+end]))}, n + end + len(pasteEnd)
+	}
+
+	fields := strings.Split(params, ";")
+	args := make([][]int, len(fields))
+	for i, field := range fields {
+		args[i] = parseSubParams(field)
+	}
+
+	// Modifiers and, if reporting event types, the action are in the second field.
+	mods, action := Modifiers(0), KeyPress
+	if len(args) > 1 && len(args[1]) > 0 {
+		if args[1][0] > 1 {
+			mods = Modifiers(args[1][0]-1) & modifiersMask
+		}
+		if len(args[1]) > 1 {
+			action = keyAction(args[1][1])
+		}
+	}
+
+	key := func(k Key) (Event, int) {
+		return KeyEvent{Key: k, Modifiers: mods, Action: action}, n
+	}
+
+	switch cmd {
+	case 'I':
+		return FocusEvent{}, n
+	case 'O':
+		return BlurEvent{}, n
+	case 'A':
+		return key(KeyUp)
+	case 'B':
+		return key(KeyDown)
+	case 'C':
+		return key(KeyRight)
+	case 'D':
+		return key(KeyLeft)
+	case 'H':
+		return key(KeyHome)
+	case 'F':
+		return key(KeyEnd)
+	case 'P', 'Q', 'R', 'S':
+		return key(KeyF1 + Key(cmd-'P'))
+	case 'Z':
+		return KeyEvent{Key: KeyTab, Modifiers: ModShift}, n
+	case '~':
+		if len(args[0]) > 0 {
+			if k, ok := tildeKeys[args[0][0]]; ok {
+				return key(k)
+			}
+		}
+	case 'u':
+		if ev, ok := decodeKittyKey(args, mods, action); ok {
+			return ev, n
+		}
+	}
+
+	return nil, n
+}
+
+// parseSubParams parses colon-separated sub-parameters. Omitted values are 0.
+func parseSubParams(field string) []int {
+	if field == "" {
+		return nil
+	}
+
+	parts := strings.Split(field, ":")
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		values[i], _ = strconv.Atoi(part)
+	}
+
+	return values
+}
+
+// Keys encoded as CSI n ~.
+var tildeKeys = map[int]Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// decodeSS3 decodes a single shift 3 (SS3) sequence starting with ESC O.
+func decodeSS3(b []byte) (Event, int) {
+	if len(b) < 3 {
+		return nil, 0
+	}
+
+	switch c := b[2]; c {
+	case 'A':
+		return KeyEvent{Key: KeyUp}, 3
+	case 'B':
+		return KeyEvent{Key: KeyDown}, 3
+	case 'C':
+		return KeyEvent{Key: KeyRight}, 3
+	case 'D':
+		return KeyEvent{Key: KeyLeft}, 3
+	case 'H':
+		return KeyEvent{Key: KeyHome}, 3
+	case 'F':
+		return KeyEvent{Key: KeyEnd}, 3
+	case 'P', 'Q', 'R', 'S':
+		return KeyEvent{Key: KeyF1 + Key(c-'P')}, 3
+	}
+
+	return nil, -1
+}
+
+// Keys encoded as CSI 1 ; modifiers final, or CSI final without modifiers.
+var finalKeys = map[Key]byte{
+	KeyUp:    'A',
+	KeyDown:  'B',
+	KeyRight: 'C',
+	KeyLeft:  'D',
+	KeyHome:  'H',
+	KeyEnd:   'F',
+	KeyF1:    'P',
+	KeyF2:    'Q',
+	KeyF3:    'R',
+	KeyF4:    'S',
+}
+
+// Keys encoded as CSI n ~ using their most common encoding.
+var tildeCodes = map[Key]int{
+	KeyInsert:   2,
+	KeyDelete:   3,
+	KeyPageUp:   5,
+	KeyPageDown: 6,
+	KeyF5:       15,
+	KeyF6:       17,
+	KeyF7:       18,
+	KeyF8:       19,
+	KeyF9:       20,
+	KeyF10:      21,
+	KeyF11:      23,
+	KeyF12:      24,
+}
+
+// encodeKey encodes a KeyEvent as a terminal would using legacy encodings.
+// It returns an empty string if ev cannot be encoded.
+func encodeKey(ev KeyEvent) string {
+	var prefix string
+	mods := ev.Modifiers
+
+	switch ev.Key {
+	case KeyRune:
+		if mods&ModAlt != 0 {
+			prefix = ansi.ESC
+		}
+
+		r := ev.Rune
+		if mods&ModCtrl != 0 {
+			switch {
+			case r == ' ':
+				r = 0
+			case r >= 'a' && r <= 'z':
+				r = r - 'a' + 1
+			case r >= '\\' && r <= '_':
+				r = r - '\\' + 0x1c
+			}
+		}
+
+		return prefix + string(r)
+
+	case KeyEnter:
+		return "\r"
+	case KeyTab:
+		if mods&ModShift != 0 {
+			return ansi.CSI + "Z"
+		}
+		return "\t"
+	case KeyBackspace:
+		return "\x7f"
+	case KeyEscape:
+		return ansi.ESC
+	}
+
+	param := ""
+	if mods != 0 {
+		param = fmt.Sprintf(";%d", int(mods)+1)
+	}
+
+	if final, ok := finalKeys[ev.Key]; ok {
+		if param != "" {
+			return ansi.CSI + "1" + param + string(final)
+		}
+		if ev.Key >= KeyF1 {
+			return ansi.ESC + "O" + string(final)
+		}
+		return ansi.CSI + string(final)
+	}
+
+	if n, ok := tildeCodes[ev.Key]; ok {
+		return fmt.Sprintf(ansi.CSI+"%d%s~", n, param)
+	}
+
+	return ""
+}
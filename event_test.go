@@ -0,0 +1,310 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConsole_ReadEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Event
+	}{
+		{
+			name:  "runes",
+			input: "aé世",
+			want: []Event{
+				KeyEvent{Key: KeyRune, Rune: 'a'},
+				KeyEvent{Key: KeyRune, Rune: 'é'},
+				KeyEvent{Key: KeyRune, Rune: '世'},
+			},
+		},
+		{
+			name:  "control keys",
+			input: "\r\t\x7f\x01\x17\x00\x1f",
+			want: []Event{
+				KeyEvent{Key: KeyEnter},
+				KeyEvent{Key: KeyTab},
+				KeyEvent{Key: KeyBackspace},
+				KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModCtrl},
+				KeyEvent{Key: KeyRune, Rune: 'w', Modifiers: ModCtrl},
+				KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl},
+				KeyEvent{Key: KeyRune, Rune: '_', Modifiers: ModCtrl},
+			},
+		},
+		{
+			name:  "escape",
+			input: "\x1b",
+			want: []Event{
+				KeyEvent{Key: KeyEscape},
+			},
+		},
+		{
+			name:  "double escape",
+			input: "\x1b\x1b",
+			want: []Event{
+				KeyEvent{Key: KeyEscape},
+				KeyEvent{Key: KeyEscape},
+			},
+		},
+		{
+			name:  "alt",
+			input: "\x1bb\x1b\x7f",
+			want: []Event{
+				KeyEvent{Key: KeyRune, Rune: 'b', Modifiers: ModAlt},
+				KeyEvent{Key: KeyBackspace, Modifiers: ModAlt},
+			},
+		},
+		{
+			name:  "cursor keys",
+			input: "\x1b[A\x1b[B\x1b[1;5C\x1b[1;2D\x1bOH\x1b[F",
+			want: []Event{
+				KeyEvent{Key: KeyUp},
+				KeyEvent{Key: KeyDown},
+				KeyEvent{Key: KeyRight, Modifiers: ModCtrl},
+				KeyEvent{Key: KeyLeft, Modifiers: ModShift},
+				KeyEvent{Key: KeyHome},
+				KeyEvent{Key: KeyEnd},
+			},
+		},
+		{
+			name:  "tilde keys",
+			input: "\x1b[3~\x1b[5;3~\x1b[6~\x1b[24~",
+			want: []Event{
+				KeyEvent{Key: KeyDelete},
+				KeyEvent{Key: KeyPageUp, Modifiers: ModAlt},
+				KeyEvent{Key: KeyPageDown},
+				KeyEvent{Key: KeyF12},
+			},
+		},
+		{
+			name:  "function keys",
+			input: "\x1bOP\x1b[1;2Q\x1b[15~",
+			want: []Event{
+				KeyEvent{Key: KeyF1},
+				KeyEvent{Key: KeyF2, Modifiers: ModShift},
+				KeyEvent{Key: KeyF5},
+			},
+		},
+		{
+			name:  "shift tab",
+			input: "\x1b[Z",
+			want: []Event{
+				KeyEvent{Key: KeyTab, Modifiers: ModShift},
+			},
+		},
+		{
+			name:  "paste",
+			input: "a\x1b[200~line 1\r\nline 2\rline 3\x1b[201~b",
+			want: []Event{
+				KeyEvent{Key: KeyRune, Rune: 'a'},
+				PasteEvent{Text: "line 1\nline 2\nline 3"},
+				KeyEvent{Key: KeyRune, Rune: 'b'},
+			},
+		},
+		{
+			name:  "incomplete paste",
+			input: "\x1b[200~text",
+			want: []Event{
+				PasteEvent{Text: "text"},
+			},
+		},
+		{
+			name:  "focus",
+			input: "\x1b[O\x1b[I",
+			want: []Event{
+				BlurEvent{},
+				FocusEvent{},
+			},
+		},
+		{
+			name:  "unrecognized",
+			input: "\x1b[99~a",
+			want: []Event{
+				KeyEvent{Key: KeyRune, Rune: 'a'},
+			},
+		},
+		{
+			name:  "strings",
+			input: "\x1b]11;rgb:ffff/ffff/ffff\x1b\\a\x1b]0;title\ab\x1bP1$r0m\x1b\\\x1b_apc\x1b\\\x1b^pm\x1b[Ac",
+			want: []Event{
+				KeyEvent{Key: KeyRune, Rune: 'a'},
+				KeyEvent{Key: KeyRune, Rune: 'b'},
+				KeyEvent{Key: KeyUp},
+				KeyEvent{Key: KeyRune, Rune: 'c'},
+			},
+		},
+		{
+			name:  "alt bracket",
+			input: "\x1b]",
+			want: []Event{
+				KeyEvent{Key: KeyRune, Rune: ']', Modifiers: ModAlt},
+			},
+		},
+		{
+			name:  "incomplete",
+			input: "\x1b[1;",
+			want: []Event{
+				KeyEvent{Key: KeyEscape},
+				KeyEvent{Key: KeyRune, Rune: '['},
+				KeyEvent{Key: KeyRune, Rune: '1'},
+				KeyEvent{Key: KeyRune, Rune: ';'},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+			)
+
+			var got []Event
+			for {
+				ev, err := f.ReadEvent()
+				if errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					t.Fatalf("ReadEvent() error = %v", err)
+				}
+				got = append(got, ev)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ReadEvent() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyEvent_String(t *testing.T) {
+	tests := []struct {
+		ev   KeyEvent
+		want string
+	}{
+		{ev: KeyEvent{Key: KeyRune, Rune: 'a'}, want: "a"},
+		{ev: KeyEvent{Key: KeyRune, Rune: 'c', Modifiers: ModCtrl}, want: "Ctrl+c"},
+		{ev: KeyEvent{Key: KeyUp, Modifiers: ModShift | ModAlt}, want: "Shift+Alt+Up"},
+		{ev: KeyEvent{Key: Key(100)}, want: "Key(100)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.ev.String(); got != tt.want {
+				t.Fatalf("String() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_ReadEvent_pasteChunks(t *testing.T) {
+	r, w := io.Pipe()
+	c := &con{stdin: r}
+
+	go func() {
+		for _, s := range []string{"\x1b[200~", "multi-", "line\r", "text\x1b[2", "01~"} {
+			w.Write([]byte(s)) // nolint:errcheck
+			time.Sleep(2 * escapeTimeout)
+		}
+		w.Close()
+	}()
+
+	ev, err := c.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent() error = %v", err)
+	}
+
+	want := PasteEvent{Text: "multi-line\ntext"}
+	if ev != want {
+		t.Fatalf("ReadEvent() = %#v, expected %#v", ev, want)
+	}
+}
+
+func TestEncodeKey(t *testing.T) {
+	tests := []KeyEvent{
+		{Key: KeyRune, Rune: 'a'},
+		{Key: KeyRune, Rune: '世'},
+		{Key: KeyRune, Rune: 'c', Modifiers: ModCtrl},
+		{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl},
+		{Key: KeyRune, Rune: 'b', Modifiers: ModAlt},
+		{Key: KeyEnter},
+		{Key: KeyTab},
+		{Key: KeyTab, Modifiers: ModShift},
+		{Key: KeyBackspace},
+		{Key: KeyUp},
+		{Key: KeyLeft, Modifiers: ModCtrl},
+		{Key: KeyHome},
+		{Key: KeyEnd, Modifiers: ModShift},
+		{Key: KeyPageDown},
+		{Key: KeyDelete, Modifiers: ModAlt},
+		{Key: KeyF1},
+		{Key: KeyF4, Modifiers: ModShift},
+		{Key: KeyF12},
+	}
+
+	for _, want := range tests {
+		t.Run(want.String(), func(t *testing.T) {
+			b := []byte(encodeKey(want))
+			got, n := decodeEvent(b, true)
+			if n != len(b) {
+				t.Fatalf("decodeEvent(%q) consumed %d bytes, expected %d", b, n, len(b))
+			}
+			if got != want {
+				t.Fatalf("decodeEvent(%q) = %v, expected %v", b, got, want)
+			}
+		})
+	}
+}
+
+func TestConsole_ReadEvent_query(t *testing.T) {
+	f := Fake(
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	var got Event
+	f.Start(func() error {
+		var err error
+		got, err = f.ReadEvent()
+		return err
+	})
+
+	// Give ReadEvent time to start waiting for input.
+	time.Sleep(50 * time.Millisecond)
+
+	// Querying the terminal should not wait for ReadEvent to return.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.SynchronizedUpdate(func() {})
+	}()
+
+	if err := f.ExpectString("\x1b[?2026$p\x1b[c"); err != nil {
+		t.Fatal(err)
+	}
+	// nolint:errcheck
+	f.stdin.(*fakeBuffer).Write([]byte("\x1b[?2026;2$y\x1b[?62c"))
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("SynchronizedUpdate() blocked by ReadEvent()")
+	}
+
+	// The response should be read by the query and not ReadEvent.
+	f.SendKeys(KeyEvent{Key: KeyRune, Rune: 'a'})
+	if err := f.Wait(); err != nil {
+		t.Fatalf("ReadEvent() error = %v", err)
+	}
+	if want := (KeyEvent{Key: KeyRune, Rune: 'a'}); got != want {
+		t.Fatalf("ReadEvent() = %v, expected %v", got, want)
+	}
+	if !f.knownSynchronizedOutput() {
+		t.Fatal("SynchronizedUpdate() did not read the response")
+	}
+}
@@ -3,8 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/signal"
 	"time"
 
 	"github.com/heaths/go-console"
@@ -14,8 +12,11 @@ func main() {
 	con := console.System()
 	cs := con.ColorScheme()
 
+	// Restore the terminal when returning, panicking, or interrupted.
+	defer con.Guard().Close()
+
 	con.StartAlternativeScreenBuffer()
-	defer con.StopAlternativeScreenBuffer()
+	con.HideCursor()
 
 	con.MoveCursor(2, 2)
 	fmt.Fprintln(con, "Shall we play a game?")
@@ -24,19 +25,17 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	ctx, _ = signal.NotifyContext(ctx, os.Interrupt)
 	for {
-		con.ClearLine()
-		con.CursorColumn(2)
-		fmt.Fprintf(con, cs.LightBlack("Launching in %d..."), int(timeout.Seconds()))
+		con.SynchronizedUpdate(func() {
+			con.ClearLine()
+			con.CursorColumn(2)
+			fmt.Fprintf(con, cs.LightBlack("Launching in %d..."), int(timeout.Seconds()))
+		})
 
 		select {
 		case <-time.After(time.Second):
 			timeout -= time.Second
 		case <-ctx.Done():
-			con.StopAlternativeScreenBuffer()
-			cancel()
-
 			return
 		}
 	}
@@ -0,0 +1,199 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultExpectTimeout is how long to wait for expected output by default.
+const defaultExpectTimeout = 5 * time.Second
+
+// WithExpectTimeout sets how long ExpectString, ExpectRegexp, WaitFor, and
+// Wait wait before returning an error. The default is 5 seconds. It has no
+// effect on a Console created by New.
+func WithExpectTimeout(timeout time.Duration) Option {
+	return func(c *con) {
+		c.fakeTimeout = timeout
+	}
+}
+
+// Start calls fn on a separate goroutine to script interaction with it using
+// ExpectString, ExpectRegexp, SendLine, and SendKeys. Until Wait is called,
+// reading Stdin waits for input to be sent instead of returning io.EOF.
+func (f *FakeConsole) Start(fn func() error) {
+	if f.run != nil {
+		panic("already started")
+	}
+
+	f.stdin.(*fakeBuffer).setBlocking(true)
+	r := &fakeRun{done: make(chan struct{})}
+	f.run = r
+
+	go func() {
+		defer close(r.done)
+		r.err = fn()
+	}()
+}
+
+// Wait closes Stdin and waits for the function passed to Start to return,
+// and returns its error. If the function does not return before the timeout
+// set by WithExpectTimeout, Wait returns an error and Start may be called
+// again.
+func (f *FakeConsole) Wait() error {
+	r := f.run
+	if r == nil {
+		return errors.New("not started")
+	}
+
+	in := f.stdin.(*fakeBuffer)
+	// nolint:errcheck
+	in.Close()
+
+	// Reset state even if the function does not return in time.
+	defer func() {
+		in.setBlocking(false)
+		f.run = nil
+	}()
+
+	timer := time.NewTimer(f.fakeTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-r.done:
+	case <-timer.C:
+		return f.timeoutError("function to return")
+	}
+
+	return r.err
+}
+
+// ExpectString waits until s is written to Stdout after any previous match.
+// Output including escape sequences is matched as written.
+func (f *FakeConsole) ExpectString(s string) error {
+	_, err := f.expect(fmt.Sprintf("%q", s), func(b []byte) ([]int, []string) {
+		if i := bytes.Index(b, []byte(s)); i >= 0 {
+			return []int{i, i + len(s)}, nil
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ExpectRegexp waits until output matching re is written to Stdout after any
+// previous match, and returns the match and any submatches.
+func (f *FakeConsole) ExpectRegexp(re *regexp.Regexp) ([]string, error) {
+	return f.expect(fmt.Sprintf("/%s/", re), func(b []byte) ([]int, []string) {
+		loc := re.FindSubmatchIndex(b)
+		if loc == nil {
+			return nil, nil
+		}
+
+		matches := make([]string, len(loc)/2)
+		for i := range matches {
+			if loc[2*i] >= 0 {
+				matches[i] = string(b[loc[2*i]:loc[2*i+1]])
+			}
+		}
+		return loc[:2], matches
+	})
+}
+
+// SendLine sends line followed by a newline to Stdin.
+func (f *FakeConsole) SendLine(line string) {
+	// nolint:errcheck
+	f.stdin.(*fakeBuffer).Write([]byte(line + "\n"))
+}
+
+// SendKeys sends keys to Stdin as a terminal would encode them. It panics if
+// a key cannot be encoded.
+func (f *FakeConsole) SendKeys(keys ...KeyEvent) {
+	var sb strings.Builder
+	for _, key := range keys {
+		s := encodeKey(key)
+		if s == "" {
+			panic(fmt.Sprintf("cannot encode key %v", key))
+		}
+		sb.WriteString(s)
+	}
+
+	// nolint:errcheck
+	f.stdin.(*fakeBuffer).Write([]byte(sb.String()))
+}
+
+// WaitFor waits until fn returns true for a Snapshot of the buffers, and
+// returns that Snapshot. It returns an error if fn does not return true before
+// the timeout set by WithExpectTimeout.
+func (f *FakeConsole) WaitFor(fn func(Snapshot) bool) (Snapshot, error) {
+	deadline := time.Now().Add(f.fakeTimeout)
+	for {
+		if snapshot := f.Snapshot(); fn(snapshot) {
+			return snapshot, nil
+		}
+
+		if time.Now().After(deadline) {
+			return Snapshot{}, f.timeoutError("condition")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (f *FakeConsole) expect(what string, match func([]byte) ([]int, []string)) ([]string, error) {
+	deadline := time.Now().Add(f.fakeTimeout)
+	for {
+		// Check whether the function returned before matching output it wrote.
+		returned := false
+		r := f.run
+		if r != nil {
+			select {
+			case <-r.done:
+				returned = true
+			default:
+			}
+		}
+
+		b := f.stdout.(*fakeBuffer).Bytes()
+		if f.offset <= len(b) {
+			if loc, matches := match(b[f.offset:]); loc != nil {
+				if matches == nil {
+					matches = []string{string(b[f.offset+loc[0] : f.offset+loc[1]])}
+				}
+				f.offset += loc[1]
+				return matches, nil
+			}
+		}
+
+		if returned {
+			return nil, fmt.Errorf("function returned waiting for %s: %v\n%s", what, r.err, f.screen())
+		}
+		if time.Now().After(deadline) {
+			return nil, f.timeoutError(what)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// fakeRun is the state of a function passed to Start.
+type fakeRun struct {
+	done chan struct{}
+	err  error
+}
+
+func (f *FakeConsole) timeoutError(what string) error {
+	return fmt.Errorf("timed out after %v waiting for %s\n%s", f.fakeTimeout, what, f.screen())
+}
+
+// screen formats the screen contents for an error.
+func (f *FakeConsole) screen() string {
+	var sb strings.Builder
+	sb.WriteString("screen:\n")
+	for _, line := range strings.Split(f.Screen().String(), "\n") {
+		sb.WriteString("  | " + line + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
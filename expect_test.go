@@ -0,0 +1,154 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeConsole_Expect(t *testing.T) {
+	f := Fake(
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	var name string
+	var picked []int
+	f.Start(func() error {
+		var err error
+		if name, err = f.ReadLine("Name: "); err != nil {
+			return err
+		}
+
+		picked, err = f.Pick("Color: ", []string{"red", "green", "blue"})
+		return err
+	})
+
+	if err := f.ExpectString("Name: "); err != nil {
+		t.Fatal(err)
+	}
+	f.SendLine("heath")
+
+	if err := f.ExpectString("blue"); err != nil {
+		t.Fatal(err)
+	}
+	f.SendKeys(KeyEvent{Key: KeyDown}, KeyEvent{Key: KeyEnter})
+
+	if err := f.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	if name != "heath" {
+		t.Fatalf("ReadLine() = %q, expected %q", name, "heath")
+	}
+	if want := []int{1}; !reflect.DeepEqual(picked, want) {
+		t.Fatalf("Pick() = %v, expected %v", picked, want)
+	}
+}
+
+func TestFakeConsole_ExpectRegexp(t *testing.T) {
+	f := Fake()
+	f.Start(func() error {
+		fmt.Fprint(f.Stdout(), "a=1\nb=2\n")
+		return nil
+	})
+
+	re := regexp.MustCompile(`(\w)=(\d)`)
+	for _, want := range [][]string{{"a=1", "a", "1"}, {"b=2", "b", "2"}} {
+		got, err := f.ExpectRegexp(re)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("ExpectRegexp() = %q, expected %q", got, want)
+		}
+	}
+
+	if err := f.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+func TestFakeConsole_Expect_timeout(t *testing.T) {
+	f := Fake(WithExpectTimeout(50 * time.Millisecond))
+	f.Start(func() error {
+		fmt.Fprintln(f.Stdout(), "hello")
+		_, err := f.ReadLine("")
+		return err
+	})
+
+	err := f.ExpectString("goodbye")
+	if err == nil {
+		t.Fatal("ExpectString() expected error")
+	}
+	if want := "timed out after 50ms waiting for \"goodbye\"\nscreen:\n  | hello"; err.Error() != want {
+		t.Fatalf("ExpectString() error = %q, expected %q", err, want)
+	}
+
+	// Closing Stdin causes ReadLine to return io.EOF.
+	if err := f.Wait(); err == nil || !strings.Contains(err.Error(), "EOF") {
+		t.Fatalf("Wait() error = %v, expected EOF", err)
+	}
+}
+
+func TestFakeConsole_Expect_returned(t *testing.T) {
+	errBoom := errors.New("boom")
+	f := Fake()
+	f.Start(func() error {
+		fmt.Fprint(f.Stdout(), "hello")
+		return errBoom
+	})
+
+	err := f.ExpectString("goodbye")
+	if err == nil || !strings.HasPrefix(err.Error(), `function returned waiting for "goodbye": boom`) {
+		t.Fatalf("ExpectString() error = %v, expected function returned", err)
+	}
+
+	if err := f.Wait(); !errors.Is(err, errBoom) {
+		t.Fatalf("Wait() error = %v, expected %v", err, errBoom)
+	}
+}
+
+func TestFakeConsole_Wait_timeout(t *testing.T) {
+	f := Fake(WithExpectTimeout(50 * time.Millisecond))
+
+	block := make(chan struct{})
+	defer close(block)
+
+	f.Start(func() error {
+		<-block
+		return nil
+	})
+
+	err := f.Wait()
+	if err == nil || !strings.HasPrefix(err.Error(), "timed out after 50ms waiting for function to return") {
+		t.Fatalf("Wait() error = %v, expected timeout", err)
+	}
+
+	// Start can be called again after a timeout.
+	f.Start(func() error {
+		_, err := f.ReadLine("")
+		return err
+	})
+	f.SendLine("hello")
+
+	if err := f.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+func TestFakeConsole_SendKeys_unsupported(t *testing.T) {
+	f := Fake()
+
+	defer func() {
+		if r := recover(); r != "cannot encode key Key(99)" {
+			t.Fatalf("SendKeys() panic = %v, expected %q", r, "cannot encode key Key(99)")
+		}
+	}()
+
+	f.SendKeys(KeyEvent{Key: Key(99)})
+}
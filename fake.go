@@ -2,103 +2,292 @@ package console
 
 import (
 	"bytes"
+	"io"
+	"sync"
 
 	"github.com/heaths/go-console/pkg/colorscheme"
 )
 
 type FakeConsole struct {
 	*con
+
+	// Expect-style interaction.
+	offset int
+	run    *fakeRun
 }
 
-type FakeOption func(*FakeConsole)
+// FakeOption configures a FakeConsole created by Fake. It is the same as
+// Option so that New and Fake accept the same options.
+type FakeOption = Option
 
-func Fake(opts ...FakeOption) *FakeConsole {
+func Fake(opts ...Option) *FakeConsole {
 	c := &con{
-		stdout: &bytes.Buffer{},
-		stderr: &bytes.Buffer{},
-		stdin:  &bytes.Buffer{},
+		stdout:      &bytes.Buffer{},
+		stderr:      &bytes.Buffer{},
+		stdin:       &bytes.Buffer{},
+		fakeTimeout: defaultExpectTimeout,
 	}
 
-	f := &FakeConsole{c}
-
 	for _, opt := range opts {
-		opt(f)
+		opt(c)
 	}
 
+	// Synchronize access to buffers for expect-style interaction.
+	c.stdout = newFakeWriter(c.stdout)
+	c.stderr = newFakeWriter(c.stderr)
+	c.stdin = newFakeReader(c.stdin)
+
 	if c.cs == nil {
-		c.cs = colorscheme.New(colorscheme.WithTTY(c.IsStdoutTTY))
+		c.cs = colorscheme.New(
+			colorscheme.WithTTY(c.IsStdoutTTY),
+			colorscheme.WithLightBackground(c.isLightBackground),
+		)
 	}
 
-	return f
+	return &FakeConsole{con: c}
 }
 
+// Buffers gets the buffers underlying Stdout, Stderr, and Stdin, which may be
+// reset or written to e.g., to queue input. They are not safe to use while the
+// console may still be written to e.g., by a goroutine started with Start or
+// while progress is displayed; use Snapshot or WaitFor instead.
+//
+// If a Writer other than a *bytes.Buffer was passed to WithStdout or
+// WithStderr, the buffer contains what was also written to it. If a Reader
+// other than a *bytes.Buffer was passed to WithStdin, the buffer contains
+// input read from it or sent but not yet read.
 func (f *FakeConsole) Buffers() (stdout, stderr, stdin *bytes.Buffer) {
-	stdout = f.stdout.(*bytes.Buffer)
-	stderr = f.stderr.(*bytes.Buffer)
-	stdin = f.stdin.(*bytes.Buffer)
+	stdout = f.stdout.(*fakeBuffer).buf
+	stderr = f.stderr.(*fakeBuffer).buf
+	stdin = f.stdin.(*fakeBuffer).buf
 	return
 }
 
+// Snapshot is a copy of what was written to the FakeConsole buffers at the
+// same point in time.
+type Snapshot struct {
+	Stdout []byte
+	Stderr []byte
+
+	// Stdin is input not yet read.
+	Stdin []byte
+}
+
+// Snapshot gets a copy of the FakeConsole buffers. It is safe to call while
+// the console is being written to concurrently.
+func (f *FakeConsole) Snapshot() Snapshot {
+	stdout := f.stdout.(*fakeBuffer)
+	stderr := f.stderr.(*fakeBuffer)
+	stdin := f.stdin.(*fakeBuffer)
+
+	// Lock all buffers so the copies are consistent with each other.
+	stdout.lock.Lock()
+	defer stdout.lock.Unlock()
+	stderr.lock.Lock()
+	defer stderr.lock.Unlock()
+	stdin.lock.Lock()
+	defer stdin.lock.Unlock()
+
+	return Snapshot{
+		Stdout: stdout.bytes(),
+		Stderr: stderr.bytes(),
+		Stdin:  stdin.bytes(),
+	}
+}
+
+// Screen renders everything written to Stdout so far onto a new Screen the
+// size set by WithSize, or 80 columns by 24 rows by default.
+func (f *FakeConsole) Screen() *Screen {
+	width, height := defaultWidth, defaultHeight
+	if f.sizeOverride != nil && f.sizeOverride.Width > 0 && f.sizeOverride.Height > 0 {
+		width, height = f.sizeOverride.Width, f.sizeOverride.Height
+	}
+
+	s := NewScreen(width, height)
+	// nolint:errcheck
+	s.Write(f.stdout.(*fakeBuffer).Bytes())
+
+	return s
+}
+
+// SendMouse writes a MouseEvent to Stdin as the terminal would report it,
+// to be read by ReadEvent.
+func (f *FakeConsole) SendMouse(ev MouseEvent) {
+	// nolint:errcheck
+	f.stdin.(*fakeBuffer).Write([]byte(encodeSGRMouse(ev)))
+}
+
 func (f *FakeConsole) Write(p []byte) (n int, err error) {
-	return f.stdout.Write(p)
+	return f.out().Write(p)
 }
 
-func WithStdout(stdout *bytes.Buffer) FakeOption {
-	return func(f *FakeConsole) {
-		f.stdout = stdout
+func WithColorScheme(cs *colorscheme.ColorScheme) Option {
+	return func(c *con) {
+		c.cs = cs
 	}
 }
 
-func WithStdoutTTY(tty bool) FakeOption {
-	return func(f *FakeConsole) {
-		f.stdoutOverride = &tty
+// WithBackground sets the background color returned by Background() without
+// querying the terminal.
+func WithBackground(r, g, b uint8) Option {
+	return func(c *con) {
+		c.background = &Background{R: r, G: g, B: b}
 	}
 }
 
-func WithStderr(stderr *bytes.Buffer) FakeOption {
-	return func(f *FakeConsole) {
-		f.stderr = stderr
+// WithPager sets paged to true when StartPager is called instead of starting
+// a pager. Output is still written to Stdout.
+func WithPager(paged *bool) Option {
+	return func(c *con) {
+		c.fakePager = paged
 	}
 }
 
-func WithStderrTTY(tty bool) FakeOption {
-	return func(f *FakeConsole) {
-		f.stderrOverride = &tty
+// WithEditor sets a function called by Edit with the content to edit instead
+// of starting an editor. The returned content is edited as if saved by the
+// editor.
+func WithEditor(fn func(content string) (string, error)) Option {
+	return func(c *con) {
+		c.fakeEditor = fn
 	}
 }
 
-func WithStdin(stdin *bytes.Buffer) FakeOption {
-	return func(f *FakeConsole) {
-		f.stdin = stdin
+// fakeBuffer synchronizes access to a bytes.Buffer. If blocking, reads wait
+// for data to be written until closed instead of returning io.EOF.
+type fakeBuffer struct {
+	buf      *bytes.Buffer
+	blocking bool
+	closed   bool
+	lock     sync.Mutex
+	cond     *sync.Cond
+
+	// Writes are also written to w, if set.
+	w io.Writer
+
+	// Data is copied from r into buf once read, if set, until r returns err.
+	r       io.Reader
+	copying bool
+	err     error
+}
+
+func newFakeBuffer(buf *bytes.Buffer) *fakeBuffer {
+	b := &fakeBuffer{buf: buf}
+	b.cond = sync.NewCond(&b.lock)
+	return b
+}
+
+// newFakeWriter gets a fakeBuffer for w, or that also writes to w if w is not
+// a *bytes.Buffer.
+func newFakeWriter(w io.Writer) *fakeBuffer {
+	if buf, ok := w.(*bytes.Buffer); ok {
+		return newFakeBuffer(buf)
 	}
+
+	b := newFakeBuffer(&bytes.Buffer{})
+	b.w = w
+	return b
 }
 
-func WithStdinTTY(tty bool) FakeOption {
-	return func(f *FakeConsole) {
-		f.stdinOverride = &tty
+// newFakeReader gets a fakeBuffer for r, or that reads from r if r is not a
+// *bytes.Buffer.
+func newFakeReader(r io.Reader) *fakeBuffer {
+	if buf, ok := r.(*bytes.Buffer); ok {
+		return newFakeBuffer(buf)
 	}
+
+	b := newFakeBuffer(&bytes.Buffer{})
+	b.r = r
+	return b
 }
 
-func WithSize(width, height int) FakeOption {
-	if width < 0 {
-		panic("width cannot be less than 0")
+func (b *fakeBuffer) Read(p []byte) (n int, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.r != nil && !b.copying {
+		b.copying = true
+		go b.copyFrom(b.r)
 	}
-	if height < 0 {
-		panic("height cannot be less than 0")
+
+	for b.buf.Len() == 0 && (b.blocking || b.r != nil) && !b.closed {
+		b.cond.Wait()
 	}
-	return func(f *FakeConsole) {
-		f.sizeOverride = &struct {
-			Width  int
-			Height int
-		}{
-			Width:  width,
-			Height: height,
+
+	if b.buf.Len() == 0 {
+		if b.err != nil && b.err != io.EOF {
+			return 0, b.err
 		}
+		return 0, io.EOF
 	}
+
+	return b.buf.Read(p)
 }
 
-func WithColorScheme(cs *colorscheme.ColorScheme) FakeOption {
-	return func(f *FakeConsole) {
-		f.cs = cs
+// copyFrom copies data from r into the buffer until r returns an error.
+func (b *fakeBuffer) copyFrom(r io.Reader) {
+	p := make([]byte, 256)
+	for {
+		n, err := r.Read(p)
+
+		b.lock.Lock()
+		b.buf.Write(p[:n])
+		if err != nil {
+			b.r = nil
+			b.err = err
+		}
+		b.cond.Broadcast()
+		b.lock.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBuffer) Write(p []byte) (n int, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	defer b.cond.Broadcast()
+	if n, err = b.buf.Write(p); err != nil || b.w == nil {
+		return
 	}
+
+	return b.w.Write(p)
+}
+
+// Bytes gets a copy of the unread portion of the buffer.
+func (b *fakeBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.bytes()
+}
+
+// bytes gets a copy of the unread portion of the buffer. The caller must hold
+// lock.
+func (b *fakeBuffer) bytes() []byte {
+	p := make([]byte, b.buf.Len())
+	copy(p, b.buf.Bytes())
+	return p
+}
+
+// setBlocking sets whether reads wait for data to be written.
+func (b *fakeBuffer) setBlocking(blocking bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.blocking = blocking
+	b.closed = false
+	b.cond.Broadcast()
+}
+
+// Close causes blocked reads to return io.EOF when no data remains.
+func (b *fakeBuffer) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.closed = true
+	b.cond.Broadcast()
+	return nil
 }
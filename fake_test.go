@@ -1,9 +1,15 @@
 package console
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestWithStdoutTTY(t *testing.T) {
@@ -59,6 +65,126 @@ func TestFakeConsole_StartProgress(t *testing.T) {
 	}
 }
 
+func TestFakeConsole_StartProgress_noQuery(t *testing.T) {
+	f := Fake(
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+		WithStderrTTY(true),
+	)
+
+	f.StartProgress("progress")
+	f.StopProgress()
+
+	// Progress should not query the terminal for synchronized output.
+	stdout, _, _ := f.Buffers()
+	if got := stdout.String(); got != "" {
+		t.Fatalf("StartProgress() wrote %q to stdout, expected nothing", got)
+	}
+}
+
+func TestFakeConsole_Snapshot(t *testing.T) {
+	f := Fake(WithStdin(bytes.NewBufferString("input")))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				fmt.Fprint(f, "o")
+				fmt.Fprint(f.Stderr(), "e")
+				_ = f.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := f.Snapshot()
+	if expected := strings.Repeat("o", 1000); string(got.Stdout) != expected {
+		t.Fatalf("Snapshot().Stdout = %q, expected %q", got.Stdout, expected)
+	}
+	if expected := strings.Repeat("e", 1000); string(got.Stderr) != expected {
+		t.Fatalf("Snapshot().Stderr = %q, expected %q", got.Stderr, expected)
+	}
+	if string(got.Stdin) != "input" {
+		t.Fatalf("Snapshot().Stdin = %q, expected %q", got.Stdin, "input")
+	}
+
+	// Snapshots are copies.
+	got.Stdout[0] = 'x'
+	if f.Snapshot().Stdout[0] != 'o' {
+		t.Fatalf("Snapshot() returned underlying buffer")
+	}
+}
+
+func TestFakeConsole_WaitFor(t *testing.T) {
+	f := Fake(WithExpectTimeout(time.Second))
+
+	go func() {
+		for i := 1; i <= 3; i++ {
+			time.Sleep(10 * time.Millisecond)
+			fmt.Fprintf(f, "line %d\n", i)
+		}
+	}()
+
+	got, err := f.WaitFor(func(s Snapshot) bool {
+		return bytes.Contains(s.Stdout, []byte("line 3"))
+	})
+	if err != nil {
+		t.Fatalf("WaitFor() error = %v", err)
+	}
+	if expected := "line 1\nline 2\nline 3\n"; string(got.Stdout) != expected {
+		t.Fatalf("WaitFor() = %q, expected %q", got.Stdout, expected)
+	}
+}
+
+func TestFakeConsole_WaitFor_timeout(t *testing.T) {
+	f := Fake(WithExpectTimeout(50 * time.Millisecond))
+	fmt.Fprint(f, "hello")
+
+	_, err := f.WaitFor(func(s Snapshot) bool {
+		return len(s.Stderr) > 0
+	})
+	if err == nil {
+		t.Fatalf("WaitFor() expected error")
+	}
+	if !strings.Contains(err.Error(), "  | hello") {
+		t.Fatalf("WaitFor() error = %q, expected screen", err)
+	}
+}
+
+func TestFake_streams(t *testing.T) {
+	tee := &strings.Builder{}
+	r, w := io.Pipe()
+
+	f := Fake(WithStdout(tee), WithStdin(r), WithExpectTimeout(time.Second))
+
+	fmt.Fprint(f, "hello")
+	if got := tee.String(); got != "hello" {
+		t.Fatalf("Write() wrote %q to Writer, expected %q", got, "hello")
+	}
+	if stdout, _, _ := f.Buffers(); stdout.String() != "hello" {
+		t.Fatalf("Write() wrote %q to buffer, expected %q", stdout.String(), "hello")
+	}
+
+	go func() {
+		fmt.Fprint(w, "line\n")
+		w.Close()
+	}()
+
+	got, err := f.ReadLine("> ")
+	if err != nil {
+		t.Fatalf("ReadLine() error = %v", err)
+	}
+	if got != "line" {
+		t.Fatalf("ReadLine() = %q, expected %q", got, "line")
+	}
+
+	if _, err := f.ReadLine("> "); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadLine() error = %v, expected %v", err, io.EOF)
+	}
+}
+
 var (
 	emptyValues = []reflect.Value{}
 	falseValue  = reflect.ValueOf(false)
@@ -67,7 +193,7 @@ var (
 	trueValues  = []reflect.Value{trueValue}
 )
 
-var withFuncs = map[string]func(bool) FakeOption{
+var withFuncs = map[string]func(bool) Option{
 	"WithStdoutTTY": WithStdoutTTY,
 	"WithStderrTTY": WithStderrTTY,
 	"WithStdinTTY":  WithStdinTTY,
@@ -82,7 +208,7 @@ func testSetTTY(f *FakeConsole, s string, t *testing.T) {
 		t.Fatalf("Is%sTTY() = true, expected fallback to false", s)
 	}
 
-	fValues := []reflect.Value{reflect.ValueOf(f)}
+	fValues := []reflect.Value{reflect.ValueOf(f.con)}
 
 	setter.Call(trueValues)[0].Call(fValues)
 	if !getter.Call(emptyValues)[0].Bool() {
@@ -0,0 +1,681 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/heaths/go-console/internal/text"
+)
+
+// Names of Theme styles used by Form. If not defined by the Themes of the
+// ColorScheme, default styles are used.
+const (
+	// ThemeFormCursor styles the marker beside the current field.
+	ThemeFormCursor = "form.cursor"
+
+	// ThemeFormError styles validation errors beneath a field.
+	ThemeFormError = "form.error"
+)
+
+// FormOption configures Form.
+type FormOption func(*form)
+
+// WithFormValues sets field values by key. Values are used as defaults when
+// interactive, or to populate fields when not.
+func WithFormValues(values map[string]string) FormOption {
+	return func(f *form) {
+		f.values = values
+	}
+}
+
+// WithFormEnv sets field values from environment variables named by prefix
+// and the upper-case key, with dashes replaced by underscores: prefix "APP_"
+// and key "log-level" reads APP_LOG_LEVEL. Values set by WithFormValues take
+// precedence.
+func WithFormEnv(prefix string) FormOption {
+	return func(f *form) {
+		f.env = true
+		f.envPrefix = prefix
+	}
+}
+
+// WithFormValidator adds a function to validate the field with key after
+// built-in validation passes.
+func WithFormValidator(key string, fn func(value string) error) FormOption {
+	return func(f *form) {
+		if f.validators == nil {
+			f.validators = make(map[string]func(string) error)
+		}
+		f.validators[key] = fn
+	}
+}
+
+type fieldKind int
+
+const (
+	fieldText fieldKind = iota
+	fieldPassword
+	fieldSelect
+	fieldCheckbox
+	fieldNumber
+)
+
+type formField struct {
+	key      string
+	label    string
+	kind     fieldKind
+	required bool
+	options  []string
+	min, max *float64
+	value    reflect.Value
+	validate func(string) error
+
+	input string
+	err   error
+}
+
+type form struct {
+	c          *con
+	fields     []*formField
+	values     map[string]string
+	env        bool
+	envPrefix  string
+	validators map[string]func(string) error
+
+	current    int
+	confirming bool
+	row        int
+}
+
+// Form prompts for the exported fields of the struct v points to, and sets
+// them when the form is submitted. Current field values are used as defaults.
+//
+// Fields are configured using struct tags:
+//
+//	type Settings struct {
+//		Name     string  `form:"name,required" label:"Your name"`
+//		Token    string  `form:"token,password"`
+//		Region   string  `options:"us,eu,asia"`
+//		Verbose  bool    `label:"Verbose logging"`
+//		Retries  int     `min:"0" max:"10"`
+//		Internal string  `form:"-"`
+//	}
+//
+// The form tag sets the key, which defaults to the lower-case field name, and
+// flags "password" to mask input and "required" to require a value. The label
+// tag defaults to the field name. String fields with options are selected
+// using the arrow keys; bool fields are toggled using Space; and numeric
+// fields validate min and max if specified.
+//
+// If Stdin or Stdout is not a terminal, fields are set from WithFormValues or
+// WithFormEnv and an error is returned for the first invalid field. Otherwise,
+// Tab and Shift+Tab move between fields, Enter moves to the next field or
+// asks to submit the form after the last field, and Esc or Ctrl+C returns
+// ErrInterrupted.
+func (c *con) Form(v interface{}, opts ...FormOption) error {
+	f := &form{c: c}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	fields, err := parseForm(v)
+	if err != nil {
+		return err
+	}
+	f.fields = fields
+
+	for _, field := range f.fields {
+		if value, ok := f.lookup(field.key); ok {
+			field.input = value
+		}
+		field.validate = f.validators[field.key]
+	}
+
+	if c.IsStdinTTY() && c.IsStdoutTTY() {
+		return f.run()
+	}
+
+	for _, field := range f.fields {
+		if err := field.check(); err != nil {
+			return fmt.Errorf("%s: %w", field.key, err)
+		}
+	}
+
+	return f.bind()
+}
+
+func parseForm(v interface{}) ([]*formField, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("form must be a pointer to a struct")
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	var fields []*formField
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		tag := sf.Tag.Get("form")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		field := &formField{
+			key:   parts[0],
+			label: sf.Tag.Get("label"),
+			value: rv.Field(i),
+		}
+		if field.key == "" {
+			field.key = strings.ToLower(sf.Name)
+		}
+		if field.label == "" {
+			field.label = sf.Name
+		}
+
+		password := false
+		for _, flag := range parts[1:] {
+			switch flag {
+			case "password":
+				password = true
+			case "required":
+				field.required = true
+			default:
+				return nil, fmt.Errorf("unknown flag %q for field %s", flag, sf.Name)
+			}
+		}
+
+		switch sf.Type.Kind() {
+		case reflect.String:
+			field.kind = fieldText
+			field.input = field.value.String()
+
+			if options, ok := sf.Tag.Lookup("options"); ok {
+				field.kind = fieldSelect
+				for _, option := range strings.Split(options, ",") {
+					field.options = append(field.options, strings.TrimSpace(option))
+				}
+				if field.input == "" {
+					field.input = field.options[0]
+				}
+			} else if password {
+				field.kind = fieldPassword
+			}
+
+		case reflect.Bool:
+			field.kind = fieldCheckbox
+			field.input = strconv.FormatBool(field.value.Bool())
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			field.kind = fieldNumber
+			field.input = fmt.Sprint(field.value.Interface())
+
+			for _, bound := range []struct {
+				name string
+				ptr  **float64
+			}{{"min", &field.min}, {"max", &field.max}} {
+				if s, ok := sf.Tag.Lookup(bound.name); ok {
+					n, err := strconv.ParseFloat(s, 64)
+					if err != nil {
+						return nil, fmt.Errorf("invalid %s %q for field %s", bound.name, s, sf.Name)
+					}
+					*bound.ptr = &n
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported type %s for field %s", sf.Type, sf.Name)
+		}
+
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("form has no fields")
+	}
+
+	return fields, nil
+}
+
+func (f *form) lookup(key string) (string, bool) {
+	if value, ok := f.values[key]; ok {
+		return value, true
+	}
+
+	if f.env {
+		name := f.envPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		return os.LookupEnv(name)
+	}
+
+	return "", false
+}
+
+// parse converts the input to a value assignable to the field.
+func (field *formField) parse() (reflect.Value, error) {
+	t := field.value.Type()
+	v := reflect.New(t).Elem()
+
+	switch field.kind {
+	case fieldCheckbox:
+		b, err := strconv.ParseBool(field.input)
+		if err != nil {
+			return v, errors.New("must be true or false")
+		}
+		v.SetBool(b)
+
+	case fieldNumber:
+		if field.input == "" {
+			return v, nil
+		}
+
+		var n float64
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(field.input, t.Bits())
+			if err != nil {
+				return v, errors.New("must be a number")
+			}
+			v.SetFloat(f)
+			n = f
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(field.input, 10, t.Bits())
+			if err != nil {
+				return v, errors.New("must be a non-negative whole number")
+			}
+			v.SetUint(u)
+			n = float64(u)
+
+		default:
+			i, err := strconv.ParseInt(field.input, 10, t.Bits())
+			if err != nil {
+				return v, errors.New("must be a whole number")
+			}
+			v.SetInt(i)
+			n = float64(i)
+		}
+
+		if field.min != nil && n < *field.min {
+			return v, fmt.Errorf("must be at least %v", *field.min)
+		}
+		if field.max != nil && n > *field.max {
+			return v, fmt.Errorf("must be at most %v", *field.max)
+		}
+
+	case fieldSelect:
+		if field.index() < 0 {
+			return v, fmt.Errorf("must be one of %s", strings.Join(field.options, ", "))
+		}
+		v.SetString(field.input)
+
+	default:
+		v.SetString(field.input)
+	}
+
+	return v, nil
+}
+
+// check validates the input and records any error.
+func (field *formField) check() error {
+	field.err = nil
+
+	if field.required && field.input == "" {
+		field.err = errors.New("required")
+	} else if _, err := field.parse(); err != nil {
+		field.err = err
+	} else if field.validate != nil {
+		field.err = field.validate(field.input)
+	}
+
+	return field.err
+}
+
+func (field *formField) index() int {
+	for i, option := range field.options {
+		if option == field.input {
+			return i
+		}
+	}
+	return -1
+}
+
+// display formats the input for display.
+func (field *formField) display() string {
+	switch field.kind {
+	case fieldPassword:
+		return strings.Repeat("*", len([]rune(field.input)))
+	case fieldSelect:
+		return "< " + field.input + " >"
+	case fieldCheckbox:
+		if field.input == "true" {
+			return "[x]"
+		}
+		return "[ ]"
+	default:
+		return field.input
+	}
+}
+
+func (f *form) bind() error {
+	values := make([]reflect.Value, len(f.fields))
+	for i, field := range f.fields {
+		v, err := field.parse()
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.key, err)
+		}
+		values[i] = v
+	}
+
+	for i, field := range f.fields {
+		field.value.Set(values[i])
+	}
+
+	return nil
+}
+
+func (f *form) run() error {
+	restore, err := f.c.makeRaw()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	f.render()
+
+	for {
+		ev, err := f.c.ReadEvent()
+		if err != nil {
+			f.clear()
+			return err
+		}
+
+		var done bool
+		switch ev := ev.(type) {
+		case PasteEvent:
+			if !f.confirming {
+				f.insert(strings.ReplaceAll(ev.Text, "\n", " "))
+			}
+
+		case KeyEvent:
+			if ev.Action == KeyRelease {
+				continue
+			}
+
+			if ev.Key == KeyRune && ev.Modifiers&ModCtrl != 0 && ev.Rune == 'c' {
+				f.clear()
+				return ErrInterrupted
+			}
+
+			if f.confirming {
+				done, err = f.handleConfirm(ev)
+			} else {
+				err = f.handleKey(ev)
+			}
+			if err != nil {
+				f.clear()
+				return err
+			}
+		}
+
+		if done {
+			f.clear()
+			if err := f.bind(); err != nil {
+				return err
+			}
+			f.summarize()
+			return nil
+		}
+
+		f.render()
+	}
+}
+
+func (f *form) handleKey(ev KeyEvent) error {
+	field := f.fields[f.current]
+	ctrl := ev.Modifiers&ModCtrl != 0
+
+	switch {
+	case ev.Key == KeyEscape:
+		return ErrInterrupted
+
+	case ev.Key == KeyEnter:
+		if field.check() != nil {
+			break
+		}
+		if f.current < len(f.fields)-1 {
+			f.current++
+			break
+		}
+
+		// Validate all fields before asking to submit.
+		for i, field := range f.fields {
+			if field.check() != nil {
+				f.current = i
+				return nil
+			}
+		}
+		f.confirming = true
+
+	case ev.Key == KeyTab && ev.Modifiers&ModShift != 0, ev.Key == KeyUp:
+		field.check() // nolint:errcheck
+		if f.current > 0 {
+			f.current--
+		}
+
+	case ev.Key == KeyTab, ev.Key == KeyDown:
+		field.check() // nolint:errcheck
+		if f.current < len(f.fields)-1 {
+			f.current++
+		}
+
+	case field.kind == fieldSelect:
+		i := field.index()
+		switch {
+		case ev.Key == KeyLeft:
+			i--
+		case ev.Key == KeyRight, ev.Key == KeyRune && ev.Rune == ' ':
+			i++
+		case ev.Key == KeyRune && !ctrl:
+			// Jump to the next option starting with the rune.
+			for j := 1; j <= len(field.options); j++ {
+				k := (i + j) % len(field.options)
+				if r := []rune(field.options[k]); len(r) > 0 && unicode.ToLower(r[0]) == unicode.ToLower(ev.Rune) {
+					i = k
+					break
+				}
+			}
+		}
+		field.input = field.options[(i+len(field.options))%len(field.options)]
+		field.err = nil
+
+	case field.kind == fieldCheckbox:
+		if ev.Key == KeyLeft || ev.Key == KeyRight || ev.Key == KeyRune && (ev.Rune == ' ' || ev.Rune == 'x') {
+			field.input = strconv.FormatBool(field.input != "true")
+			field.err = nil
+		}
+
+	case ev.Key == KeyBackspace:
+		if r := []rune(field.input); len(r) > 0 {
+			field.input = string(r[:len(r)-1])
+		}
+
+	case ctrl && ev.Rune == 'u':
+		field.input = ""
+
+	case ctrl && ev.Rune == 'w':
+		r := []rune(field.input)
+		i := len(r)
+		for i > 0 && r[i-1] == ' ' {
+			i--
+		}
+		for i > 0 && r[i-1] != ' ' {
+			i--
+		}
+		field.input = string(r[:i])
+
+	case ev.Key == KeyRune && ev.Modifiers&(ModCtrl|ModAlt|ModMeta) == 0:
+		f.insert(string(ev.Rune))
+	}
+
+	return nil
+}
+
+func (f *form) handleConfirm(ev KeyEvent) (bool, error) {
+	switch {
+	case ev.Key == KeyEnter, ev.Key == KeyRune && (ev.Rune == 'y' || ev.Rune == 'Y'):
+		return true, nil
+
+	case ev.Key == KeyEscape, ev.Key == KeyRune && (ev.Rune == 'n' || ev.Rune == 'N'):
+		f.confirming = false
+	}
+
+	return false, nil
+}
+
+// insert appends s to the current field if it accepts text.
+func (f *form) insert(s string) {
+	field := f.fields[f.current]
+	switch field.kind {
+	case fieldText, fieldPassword:
+		field.input += s
+
+	case fieldNumber:
+		for _, r := range s {
+			if unicode.IsDigit(r) || strings.ContainsRune("+-.eE", r) {
+				field.input += string(r)
+			}
+		}
+	}
+}
+
+func (f *form) labelWidth() int {
+	width := 0
+	for _, field := range f.fields {
+		if w := text.StringWidth(field.label); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+func (f *form) render() {
+	c := f.c
+	cs := c.ColorScheme()
+
+	width, _, err := c.Size()
+	if err != nil || width <= 0 {
+		width = defaultWidth
+	}
+
+	labelWidth := f.labelWidth()
+	valueWidth := width - labelWidth - 5
+	if valueWidth < 0 {
+		valueWidth = 0
+	}
+	indent := strings.Repeat(" ", labelWidth+5)
+
+	var lines []string
+	cursorLine, cursorColumn := 0, 1
+	for i, field := range f.fields {
+		marker := "  "
+		if i == f.current && !f.confirming {
+			marker = cs.ThemeFuncOr(ThemeFormCursor, "red+b")(">") + " "
+		}
+
+		label := field.label + ":" + strings.Repeat(" ", labelWidth-text.StringWidth(field.label))
+		display := text.Truncate(field.display(), valueWidth)
+
+		if i == f.current {
+			cursorLine = len(lines)
+			cursorColumn = labelWidth + 6
+			switch field.kind {
+			case fieldText, fieldPassword, fieldNumber:
+				cursorColumn += text.StringWidth(display)
+			case fieldSelect:
+				cursorColumn += 2
+			case fieldCheckbox:
+				cursorColumn++
+			}
+		}
+
+		lines = append(lines, marker+label+"  "+cs.Cyan(display))
+		if field.err != nil {
+			lines = append(lines, indent+cs.ThemeFuncOr(ThemeFormError, "red")(text.Truncate(field.err.Error(), valueWidth)))
+		}
+	}
+
+	if f.confirming {
+		prompt := text.Truncate("Submit? [Y/n] ", width)
+		lines = append(lines, cs.Yellow(prompt))
+		cursorLine = len(lines) - 1
+		cursorColumn = text.StringWidth(prompt) + 1
+	} else {
+		lines = append(lines, cs.LightBlack(text.Truncate("Tab to move, Enter to continue, Esc to cancel", width)))
+	}
+
+	if cursorColumn > width {
+		cursorColumn = width
+	}
+
+	c.SynchronizedUpdate(func() {
+		if f.row > 0 {
+			c.CursorUp(f.row)
+		}
+		c.CursorColumn(1)
+
+		for i, line := range lines {
+			if i > 0 {
+				fmt.Fprint(c.out(), "\r\n")
+			}
+			c.ClearLine()
+			fmt.Fprint(c.out(), line)
+		}
+		c.ClearToEndOfScreen()
+
+		if n := len(lines) - 1 - cursorLine; n > 0 {
+			c.CursorUp(n)
+		}
+		c.CursorColumn(cursorColumn)
+	})
+
+	f.row = cursorLine
+}
+
+// clear clears the form, leaving the cursor where the form started.
+func (f *form) clear() {
+	if f.row > 0 {
+		f.c.CursorUp(f.row)
+	}
+	f.c.CursorColumn(1)
+	f.c.ClearToEndOfScreen()
+	f.row = 0
+}
+
+func (f *form) summarize() {
+	labelWidth := f.labelWidth()
+
+	var sb strings.Builder
+	for _, field := range f.fields {
+		display := field.input
+		switch field.kind {
+		case fieldPassword, fieldCheckbox:
+			display = field.display()
+		}
+
+		sb.WriteString(field.label + ":" + strings.Repeat(" ", labelWidth-text.StringWidth(field.label)) + "  " + display + "\r\n")
+	}
+
+	fmt.Fprint(f.c.out(), sb.String())
+}
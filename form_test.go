@@ -0,0 +1,330 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/ansi"
+	"github.com/heaths/go-console/pkg/colorscheme"
+	"github.com/heaths/go-console/pkg/golden"
+)
+
+type testSettings struct {
+	Name     string  `form:"name,required" label:"Your name"`
+	Token    string  `form:"token,password"`
+	Region   string  `options:"us,eu,asia"`
+	Verbose  bool    `form:"verbose" label:"Verbose logging"`
+	Retries  int     `min:"0" max:"10"`
+	Ratio    float64 `form:"ratio"`
+	Internal string  `form:"-"`
+	private  string
+}
+
+func TestConsole_Form(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    testSettings
+		wantErr error
+		screen  []string
+	}{
+		{
+			name:  "defaults",
+			input: "heath\r\r\r\r\r\r\r",
+			want:  testSettings{Name: "heath", Region: "us"},
+			screen: []string{
+				"Your name:        heath",
+				"Token:",
+				"Region:           us",
+				"Verbose logging:  [ ]",
+				"Retries:          0",
+				"Ratio:            0",
+				"",
+			},
+		},
+		{
+			name:  "all fields",
+			input: "heath\tsecret\t\x1b[C\x1b[C\t \t\x7f3\t\x7f0.5\ry",
+			want:  testSettings{Name: "heath", Token: "secret", Region: "asia", Verbose: true, Retries: 3, Ratio: 0.5},
+			screen: []string{
+				"Your name:        heath",
+				"Token:            ******",
+				"Region:           asia",
+				"Verbose logging:  [x]",
+				"Retries:          3",
+				"Ratio:            0.5",
+			},
+		},
+		{
+			name:  "select by letter",
+			input: "heath\t\te\r\r\r\r\r",
+			want:  testSettings{Name: "heath", Region: "eu"},
+		},
+		{
+			name:  "previous field",
+			input: "\t\x1b[Zheath\r\r\r\r\r\r\r",
+			want:  testSettings{Name: "heath", Region: "us"},
+		},
+		{
+			name:  "required",
+			input: "\r\r\rheath\r\r\r\r\r\r\r",
+			want:  testSettings{Name: "heath", Region: "us"},
+		},
+		{
+			name:  "validate before submit",
+			input: "heath\t\t\t\t\x7f11\t\r\x7f\x7f1\r\r\r",
+			want:  testSettings{Name: "heath", Region: "us", Retries: 1},
+		},
+		{
+			name:  "decline",
+			input: "heath\r\r\r\r\r\rn\x1b[A\x7f7\r\ry",
+			want:  testSettings{Name: "heath", Region: "us", Retries: 7},
+		},
+		{
+			name:    "escape",
+			input:   "heath\x1b",
+			wantErr: ErrInterrupted,
+		},
+		{
+			name:    "interrupt",
+			input:   "heath\t\x03",
+			wantErr: ErrInterrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+				WithSize(40, 20),
+			)
+
+			var got testSettings
+			err := f.Form(&got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Form() error = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Fatalf("Form() = %+v, expected %+v", got, tt.want)
+			}
+
+			if tt.screen != nil {
+				if lines := f.Screen().Lines()[:len(tt.screen)]; !reflect.DeepEqual(lines, tt.screen) {
+					t.Fatalf("Screen() = %q, expected %q", lines, tt.screen)
+				}
+			}
+
+			if f.isRaw() {
+				t.Fatal("Form() did not disable raw mode")
+			}
+		})
+	}
+}
+
+func TestConsole_Form_render(t *testing.T) {
+	f := Fake(
+		WithStdin(bytes.NewBufferString("\t\t\t\t\x7f11\t")),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+		WithSize(60, 20),
+	)
+
+	var got testSettings
+	if err := f.Form(&got); err == nil {
+		t.Fatal("Form() expected error")
+	}
+
+	// Replay output up to where the form was cleared at the end of input.
+	stdout, _, _ := f.Buffers()
+	out := stdout.String()
+	screen := NewScreen(60, 20)
+	screen.Write([]byte(out[:strings.LastIndex(out, "\x1b[1G")])) // nolint:errcheck
+
+	golden.AssertString(t, "form_render", screen.String())
+}
+
+func TestConsole_Form_render_colors(t *testing.T) {
+	theme := colorscheme.Theme{ThemeFormCursor: "green", ThemeFormError: "yellow"}
+	f := Fake(
+		WithStdoutTTY(true),
+		WithSize(40, 10),
+	)
+	f.cs = colorscheme.New(
+		colorscheme.WithTTY(f.IsStdoutTTY),
+		colorscheme.WithThemes(theme, theme),
+	)
+
+	var v struct {
+		Name string
+	}
+	fields, err := parseForm(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields[0].err = errors.New("required")
+
+	frm := &form{c: f.con, fields: fields}
+	frm.render()
+
+	screen := f.Screen()
+	if got, want := screen.Cell(1, 1).Style.Foreground, ansi.IndexedColor(2); got != want {
+		t.Fatalf("cursor color = %v, expected %v", got, want)
+	}
+	if got, want := screen.Cell(2, 10).Style.Foreground, ansi.IndexedColor(3); got != want {
+		t.Fatalf("error color = %v, expected %v", got, want)
+	}
+}
+
+func TestConsole_Form_notTTY(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		env     map[string]string
+		opts    []FormOption
+		want    testSettings
+		wantErr string
+	}{
+		{
+			name:   "values",
+			values: map[string]string{"name": "heath", "token": "secret", "region": "eu", "verbose": "true", "retries": "5", "ratio": "0.25"},
+			want:   testSettings{Name: "heath", Token: "secret", Region: "eu", Verbose: true, Retries: 5, Ratio: 0.25},
+		},
+		{
+			name: "env",
+			env:  map[string]string{"TEST_NAME": "heath", "TEST_VERBOSE": "1"},
+			want: testSettings{Name: "heath", Region: "us", Verbose: true},
+		},
+		{
+			name:   "values before env",
+			values: map[string]string{"name": "heath"},
+			env:    map[string]string{"TEST_NAME": "other"},
+			want:   testSettings{Name: "heath", Region: "us"},
+		},
+		{
+			name:    "required",
+			wantErr: "name: required",
+		},
+		{
+			name:    "invalid option",
+			values:  map[string]string{"name": "heath", "region": "mars"},
+			wantErr: "region: must be one of us, eu, asia",
+		},
+		{
+			name:    "invalid number",
+			values:  map[string]string{"name": "heath", "retries": "many"},
+			wantErr: "retries: must be a whole number",
+		},
+		{
+			name:    "minimum",
+			values:  map[string]string{"name": "heath", "retries": "-1"},
+			wantErr: "retries: must be at least 0",
+		},
+		{
+			name:    "invalid bool",
+			values:  map[string]string{"name": "heath", "verbose": "maybe"},
+			wantErr: "verbose: must be true or false",
+		},
+		{
+			name:   "validator",
+			values: map[string]string{"name": "x"},
+			opts: []FormOption{
+				WithFormValidator("name", func(value string) error {
+					if len(value) < 2 {
+						return errors.New("too short")
+					}
+					return nil
+				}),
+			},
+			wantErr: "name: too short",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			f := Fake()
+			got := testSettings{Internal: "unchanged"}
+			opts := append([]FormOption{WithFormValues(tt.values), WithFormEnv("TEST_")}, tt.opts...)
+
+			err := f.Form(&got, opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Form() error = %v, expected %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Form() error = %v", err)
+			}
+
+			tt.want.Internal = "unchanged"
+			if got != tt.want {
+				t.Fatalf("Form() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_Form_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		wantErr string
+	}{
+		{
+			name:    "not a pointer",
+			v:       testSettings{},
+			wantErr: "form must be a pointer to a struct",
+		},
+		{
+			name:    "unsupported type",
+			v:       &struct{ Tags []string }{},
+			wantErr: "unsupported type []string for field Tags",
+		},
+		{
+			name: "unknown flag",
+			v: &struct {
+				Name string `form:"name,hidden"`
+			}{},
+			wantErr: `unknown flag "hidden" for field Name`,
+		},
+		{
+			name: "invalid bound",
+			v: &struct {
+				Count int `min:"zero"`
+			}{},
+			wantErr: `invalid min "zero" for field Count`,
+		},
+		{
+			name:    "no fields",
+			v:       &struct{}{},
+			wantErr: "form has no fields",
+		},
+		{
+			name: "no exported fields",
+			v: &struct {
+				name string
+			}{},
+			wantErr: "form has no fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Fake().Form(tt.v)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Form() error = %v, expected %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package console
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Scores used to rank fuzzy matches.
+const (
+	scoreMatch       = 16
+	scoreConsecutive = 8
+	scoreBoundary    = 10
+	scoreGap         = 1
+)
+
+type fuzzyResult struct {
+	index     int
+	score     int
+	positions []int
+}
+
+// fuzzyFilter returns items that fuzzily match pattern ranked from best to
+// worst, or all items in order if pattern is empty. Matching is
+// case-insensitive unless pattern contains upper case.
+func fuzzyFilter(pattern []rune, items [][]rune) []fuzzyResult {
+	caseSensitive := false
+	for _, r := range pattern {
+		if unicode.IsUpper(r) {
+			caseSensitive = true
+			break
+		}
+	}
+
+	results := make([]fuzzyResult, 0, len(items))
+	for i, item := range items {
+		if score, positions, ok := fuzzyMatch(pattern, item, caseSensitive); ok {
+			results = append(results, fuzzyResult{
+				index:     i,
+				score:     score,
+				positions: positions,
+			})
+		}
+	}
+
+	if len(pattern) == 0 {
+		return results
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return len(items[results[i].index]) < len(items[results[j].index])
+	})
+
+	return results
+}
+
+// fuzzyMatch returns whether all runes of pattern appear in s in order, a
+// score where higher is better, and the positions of matched runes in s.
+func fuzzyMatch(pattern, s []rune, caseSensitive bool) (score int, positions []int, ok bool) {
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	eq := func(a, b rune) bool {
+		if caseSensitive {
+			return a == b
+		}
+		return a == b || unicode.ToLower(a) == unicode.ToLower(b)
+	}
+
+	// Find the first end of a match.
+	end, p := -1, 0
+	for i, r := range s {
+		if eq(r, pattern[p]) {
+			p++
+			if p == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// Scan backward to find the shortest match ending there.
+	start, p := end, len(pattern)-1
+	for i := end; i >= 0; i-- {
+		if eq(s[i], pattern[p]) {
+			p--
+			if p < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	positions = make([]int, 0, len(pattern))
+	prev := -2
+	for i, p := start, 0; i <= end && p < len(pattern); i++ {
+		if !eq(s[i], pattern[p]) {
+			continue
+		}
+
+		score += scoreMatch
+		if i == prev+1 {
+			score += scoreConsecutive
+		}
+		if i == 0 || isBoundary(s[i-1], s[i]) {
+			score += scoreBoundary
+		}
+
+		positions = append(positions, i)
+		prev = i
+		p++
+	}
+
+	score -= scoreGap * (end - start + 1 - len(pattern))
+	return score, positions, true
+}
+
+// isBoundary returns true if cur starts a word after prev.
+func isBoundary(prev, cur rune) bool {
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
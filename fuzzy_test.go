@@ -0,0 +1,124 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		s             string
+		caseSensitive bool
+		wantOK        bool
+		wantPositions []int
+	}{
+		{
+			name:   "empty",
+			s:      "anything",
+			wantOK: true,
+		},
+		{
+			name:          "prefix",
+			pattern:       "con",
+			s:             "console",
+			wantOK:        true,
+			wantPositions: []int{0, 1, 2},
+		},
+		{
+			name:          "scattered",
+			pattern:       "cse",
+			s:             "console",
+			wantOK:        true,
+			wantPositions: []int{0, 3, 6},
+		},
+		{
+			name:          "shortest",
+			pattern:       "ab",
+			s:             "a_xab",
+			wantOK:        true,
+			wantPositions: []int{3, 4},
+		},
+		{
+			name:    "out of order",
+			pattern: "oc",
+			s:       "color",
+		},
+		{
+			name:          "case-insensitive",
+			pattern:       "rm",
+			s:             "README",
+			wantOK:        true,
+			wantPositions: []int{0, 4},
+		},
+		{
+			name:          "case-sensitive",
+			pattern:       "Rm",
+			s:             "README",
+			caseSensitive: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch([]rune(tt.pattern), []rune(tt.s), tt.caseSensitive)
+			if ok != tt.wantOK {
+				t.Fatalf("fuzzyMatch() ok = %v, expected %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(positions, tt.wantPositions) {
+				t.Fatalf("fuzzyMatch() positions = %v, expected %v", positions, tt.wantPositions)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	items := []string{"fuzzy_test.go", "fuzzy.go", "go.mod", "pkg/form/config.go", "FakeConsole.go"}
+	runes := make([][]rune, len(items))
+	for i, item := range items {
+		runes[i] = []rune(item)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name: "empty",
+			want: items,
+		},
+		{
+			name:    "shorter first",
+			pattern: "fuzzy",
+			want:    []string{"fuzzy.go", "fuzzy_test.go"},
+		},
+		{
+			name:    "boundaries first",
+			pattern: "fc",
+			want:    []string{"FakeConsole.go", "pkg/form/config.go"},
+		},
+		{
+			name:    "smart case",
+			pattern: "FC",
+			want:    []string{"FakeConsole.go"},
+		},
+		{
+			name:    "none",
+			pattern: "xyz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, result := range fuzzyFilter([]rune(tt.pattern), runes) {
+				got = append(got, items[result.index])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("fuzzyFilter() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
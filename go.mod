@@ -4,6 +4,7 @@ go 1.17
 
 require (
 	github.com/briandowns/spinner v1.18.1
+	golang.org/x/sys v0.2.0
 	golang.org/x/term v0.2.0
 )
 
@@ -11,5 +12,4 @@ require (
 	github.com/fatih/color v1.7.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.16 // indirect
-	golang.org/x/sys v0.2.0 // indirect
 )
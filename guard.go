@@ -0,0 +1,77 @@
+package console
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var guardSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+	syscall.SIGHUP,
+}
+
+// Guard restores terminal modes changed through a Console when closed, when
+// the goroutine that deferred Close panics, or when the process receives
+// SIGINT, SIGTERM, or SIGHUP.
+type Guard struct {
+	c       *con
+	signals chan os.Signal
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Guard returns a Guard that restores terminal modes changed through the
+// console. You should defer Close immediately:
+//
+//	con := console.System()
+//	defer con.Guard().Close()
+//
+// Upon receiving a signal, the Guard restores terminal modes and stops
+// handling signals. It does not raise the signal again or exit the process:
+// handlers the application registered with signal.Notify receive the signal
+// as usual, and if there are none, receiving the signal again has the default
+// behavior, which typically terminates the process.
+func (c *con) Guard() *Guard {
+	g := &Guard{
+		c:       c,
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}
+
+	signal.Notify(g.signals, guardSignals...)
+	go g.wait()
+
+	return g
+}
+
+func (g *Guard) wait() {
+	select {
+	case <-g.signals:
+		g.c.Restore()
+		signal.Stop(g.signals)
+
+	case <-g.done:
+	}
+}
+
+// Close restores terminal modes and stops handling signals. If deferred while
+// the goroutine is panicking, Close restores terminal modes and panics again
+// with the same value.
+func (g *Guard) Close() error {
+	r := recover()
+
+	g.once.Do(func() {
+		signal.Stop(g.signals)
+		close(g.done)
+	})
+	g.c.Restore()
+
+	if r != nil {
+		panic(r)
+	}
+
+	return nil
+}
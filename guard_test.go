@@ -0,0 +1,128 @@
+package console
+
+import (
+	"testing"
+)
+
+func TestConsole_Restore(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+		WithStdinTTY(true),
+	)
+
+	f.SetTitle("test")
+	f.StartAlternativeScreenBuffer()
+	f.HideCursor()
+	if err := f.EnableRawMode(); err != nil {
+		t.Fatalf("EnableRawMode() error = %v", err)
+	}
+
+	stdout, _, _ := f.Buffers()
+	stdout.Reset()
+
+	f.Restore()
+	if got, want := stdout.String(), "\x1b[?25h\x1b[?1049l\x1b[23;0t"; got != want {
+		t.Fatalf("Restore() wrote %q, expected %q", got, want)
+	}
+	if f.isRaw() {
+		t.Fatal("Restore() did not disable raw mode")
+	}
+
+	stdout.Reset()
+	f.Restore()
+	if stdout.Len() > 0 {
+		t.Fatalf("Restore() wrote %q, expected nothing", stdout.String())
+	}
+}
+
+func TestConsole_Restore_changed(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	f.StartAlternativeScreenBuffer()
+	f.HideCursor()
+	f.ShowCursor()
+	f.StopAlternativeScreenBuffer()
+
+	stdout, _, _ := f.Buffers()
+	stdout.Reset()
+
+	f.Restore()
+	if stdout.Len() > 0 {
+		t.Fatalf("Restore() wrote %q, expected nothing", stdout.String())
+	}
+}
+
+func TestConsole_SetTitle(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	f.SetTitle("one")
+	f.SetTitle("two")
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[22;0t\x1b]0;one\x1b\\\x1b]0;two\x1b\\"; got != want {
+		t.Fatalf("SetTitle() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestConsole_EnableRawMode_notTTY(t *testing.T) {
+	f := Fake()
+	if err := f.EnableRawMode(); err == nil {
+		t.Fatal("EnableRawMode() expected error")
+	}
+}
+
+func TestGuard_Close(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	g := f.Guard()
+	f.StartAlternativeScreenBuffer()
+
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[?1049h\x1b[?1049l"; got != want {
+		t.Fatalf("Close() wrote %q, expected %q", got, want)
+	}
+
+	// Closing again should be safe.
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestGuard_Close_panic(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	defer func() {
+		if r := recover(); r != "test" {
+			t.Fatalf("recover() = %v, expected %q", r, "test")
+		}
+
+		stdout, stderr, _ := f.Buffers()
+		if got, want := stdout.String(), "\x1b[?25l\x1b[?25h"; got != want {
+			t.Fatalf("Close() wrote %q, expected %q", got, want)
+		}
+
+		// The runtime reports the panic, so nothing else should be written.
+		if stderr.Len() > 0 {
+			t.Fatalf("Close() wrote %q to stderr, expected nothing", stderr.String())
+		}
+	}()
+
+	func() {
+		defer f.Guard().Close()
+
+		f.HideCursor()
+		panic("test")
+	}()
+}
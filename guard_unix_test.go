@@ -0,0 +1,50 @@
+//go:build !windows
+
+package console
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestGuard_signal(t *testing.T) {
+	// Handlers registered by the application should still receive signals.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	g := f.Guard()
+	defer g.Close()
+
+	f.HideCursor()
+
+	// nolint:errcheck
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+
+	select {
+	case <-signals:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for signal")
+	}
+
+	_, err := f.WaitFor(func(s Snapshot) bool {
+		return string(s.Stdout) == "\x1b[?25l\x1b[?25h"
+	})
+	if err != nil {
+		t.Fatalf("Guard did not restore terminal modes: %v", err)
+	}
+
+	// The guard should not raise the signal again.
+	select {
+	case <-signals:
+		t.Fatal("received signal again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
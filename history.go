@@ -0,0 +1,105 @@
+package console
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// historyLimit is the maximum number of entries kept in a History.
+const historyLimit = 1000
+
+// History is a list of lines read by ReadLine, optionally persisted to a file.
+type History struct {
+	entries []string
+	path    string
+	lock    sync.Mutex
+}
+
+// NewHistory creates a History persisted to the file at path, loading any
+// entries it already contains. If path is empty, the History is kept only in
+// memory.
+func NewHistory(path string) (*History, error) {
+	h := &History{
+		path: path,
+	}
+
+	if path == "" {
+		return h, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	h.trim()
+	return h, nil
+}
+
+// Add adds a line to the History unless it is empty or the same as the last
+// entry, and appends it to the file if persisted.
+func (h *History) Add(line string) error {
+	line = strings.TrimRight(strings.ReplaceAll(line, "\n", " "), "\r")
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if n := len(h.entries); n > 0 && h.entries[n-1] == line {
+		return nil
+	}
+
+	h.entries = append(h.entries, line)
+	h.trim()
+
+	if h.path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.WriteString(line + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Entries gets a copy of the entries in the History from oldest to newest.
+func (h *History) Entries() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	entries := make([]string, len(h.entries))
+	copy(entries, h.entries)
+
+	return entries
+}
+
+func (h *History) trim() {
+	if n := len(h.entries); n > historyLimit {
+		h.entries = append([]string(nil), h.entries[n-historyLimit:]...)
+	}
+}
@@ -0,0 +1,67 @@
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(path, []byte("one\n\ntwo\n"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	h, err := NewHistory(path)
+	if err != nil {
+		t.Fatalf("NewHistory() error = %v", err)
+	}
+
+	for _, line := range []string{"three", "three", "", "  ", "four"} {
+		if err := h.Add(line); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	want := []string{"one", "two", "three", "four"}
+	if got := h.Entries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Entries() = %q, expected %q", got, want)
+	}
+
+	h, err = NewHistory(path)
+	if err != nil {
+		t.Fatalf("NewHistory() error = %v", err)
+	}
+
+	if got := h.Entries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Entries() = %q after reloading, expected %q", got, want)
+	}
+}
+
+func TestHistory_limit(t *testing.T) {
+	h, _ := NewHistory("")
+	for i := 0; i < historyLimit+10; i++ {
+		h.Add(strconv.Itoa(i)) // nolint:errcheck
+	}
+
+	entries := h.Entries()
+	if len(entries) != historyLimit {
+		t.Fatalf("len(Entries()) = %d, expected %d", len(entries), historyLimit)
+	}
+	if entries[0] != "10" {
+		t.Fatalf("Entries()[0] = %q, expected %q", entries[0], "10")
+	}
+}
+
+func TestNewHistory_notExist(t *testing.T) {
+	h, err := NewHistory(filepath.Join(t.TempDir(), "history"))
+	if err != nil {
+		t.Fatalf("NewHistory() error = %v", err)
+	}
+
+	if got := h.Entries(); len(got) != 0 {
+		t.Fatalf("Entries() = %q, expected none", got)
+	}
+}
@@ -0,0 +1,267 @@
+package console
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+const queryTimeout = 200 * time.Millisecond
+
+var (
+	errNotTTY  = errors.New("not a terminal")
+	errTimeout = errors.New("timed out reading input")
+	errResized = errors.New("terminal resized")
+
+	// errCanceled is returned by readInput if canceled.
+	errCanceled = errors.New("read canceled")
+
+	// Primary device attributes (DA1) response.
+	deviceAttributes = regexp.MustCompile(`\x1b\[\?[0-9;]*c`)
+)
+
+// inputReader buffers input read from Stdin. Reads happen on a separate
+// goroutine so callers can stop waiting after a timeout without losing any
+// input that is eventually read. The lock is released while waiting so that
+// other goroutines can query the terminal while another waits for input.
+type inputReader struct {
+	r    io.Reader
+	lock *sync.Mutex
+
+	buf []byte
+	err error
+
+	// Closed when the pending read, if any, completes, or to cancel it.
+	pending chan struct{}
+	cancel  chan struct{}
+
+	// Closed when no longer suspended; see suspend.
+	suspended chan struct{}
+
+	// Closed when the query in progress, if any, completes.
+	query chan struct{}
+}
+
+// fill reads more input into the buffer, waiting up to timeout or
+// indefinitely if timeout is negative. The caller must hold lock, which is
+// released while waiting. Input may also be read or removed by another
+// goroutine while waiting.
+func (in *inputReader) fill(timeout time.Duration) error {
+	return in.fillOrResize(timeout, nil)
+}
+
+// fillOrResize reads more input into the buffer like fill, or returns
+// errResized if resized receives first.
+func (in *inputReader) fillOrResize(timeout time.Duration, resized <-chan struct{}) error {
+	if in.pending == nil && in.err == nil && in.suspended == nil {
+		in.pending = make(chan struct{})
+		in.cancel = make(chan struct{})
+		go in.read(in.pending, in.cancel)
+	}
+
+	wait := in.pending
+	if in.suspended != nil {
+		// Wait to read again until resumed.
+		wait = in.suspended
+	}
+
+	if wait != nil {
+		var expired <-chan time.Time
+		if timeout >= 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		in.lock.Unlock()
+		defer in.lock.Lock()
+
+		select {
+		case <-wait:
+		case <-expired:
+			return errTimeout
+		case <-resized:
+			return errResized
+		}
+
+		return nil
+	}
+
+	// Report an error once after any input read before it.
+	err := in.err
+	in.err = nil
+	return err
+}
+
+// read reads from r into the buffer and closes pending when done.
+func (in *inputReader) read(pending, cancel chan struct{}) {
+	p := make([]byte, 256)
+	n, err := readInput(in.r, p, cancel)
+
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	in.buf = append(in.buf, p[:n]...)
+	if n == 0 && err != errCanceled {
+		in.err = err
+	}
+	in.pending = nil
+	in.cancel = nil
+	close(pending)
+}
+
+// suspend cancels any pending read and stops reading until resumed so that
+// another process can read input from the terminal. If the pending read
+// cannot be canceled, it may still read input before the other process.
+func (in *inputReader) suspend() (resume func()) {
+	in.lock.Lock()
+	suspended := make(chan struct{})
+	in.suspended = suspended
+
+	pending := in.pending
+	if in.cancel != nil {
+		close(in.cancel)
+		in.cancel = nil
+	}
+	in.lock.Unlock()
+
+	if pending != nil && canCancelInput(in.r) {
+		<-pending
+	}
+
+	return func() {
+		in.lock.Lock()
+		defer in.lock.Unlock()
+
+		in.suspended = nil
+		close(suspended)
+	}
+}
+
+// waitForQuery waits for any query in progress to complete so that it reads
+// its response before other input is consumed. The caller must hold lock.
+func (in *inputReader) waitForQuery() {
+	for in.query != nil {
+		query := in.query
+		in.lock.Unlock()
+		<-query
+		in.lock.Lock()
+	}
+}
+
+// Read implements io.Reader and returns buffered input before reading more.
+func (in *inputReader) Read(p []byte) (n int, err error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	for in.waitForQuery(); len(in.buf) == 0; in.waitForQuery() {
+		if err = in.fill(-1); err != nil {
+			return 0, err
+		}
+	}
+
+	n = copy(p, in.buf)
+	in.buf = in.buf[n:]
+	return n, nil
+}
+
+// remove removes buf[i:j] from the buffer.
+func (in *inputReader) remove(i, j int) {
+	in.buf = append(in.buf[:i:i], in.buf[j:]...)
+}
+
+// reader gets the inputReader for Stdin, creating it if necessary.
+// The caller must hold inputLock.
+func (c *con) reader() *inputReader {
+	if c.input == nil {
+		c.input = &inputReader{
+			r:    c.stdin,
+			lock: &c.inputLock,
+		}
+	}
+
+	return c.input
+}
+
+// query writes a control sequence to Stdout followed by a primary device
+// attributes request, which nearly all terminals answer. It returns the
+// response matching re if received before the device attributes, or nil if the
+// terminal did not respond to seq.
+func (c *con) query(seq string, re *regexp.Regexp) ([]byte, error) {
+	if !c.IsStdinTTY() || !c.IsStdoutTTY() {
+		return nil, errNotTTY
+	}
+
+	// Only one query can read its response at a time.
+	c.queryLock.Lock()
+	defer c.queryLock.Unlock()
+
+	restore, err := c.makeRaw()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	c.inputLock.Lock()
+	defer c.inputLock.Unlock()
+
+	in := c.reader()
+	done := make(chan struct{})
+	in.query = done
+	defer func() {
+		in.query = nil
+		close(done)
+	}()
+
+	if _, err := c.stdout.Write([]byte(seq + ansi.CSI + "c")); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(queryTimeout)
+	for {
+		if da := deviceAttributes.FindIndex(in.buf); da != nil {
+			var resp []byte
+			if loc := re.FindIndex(in.buf[:da[0]]); loc != nil {
+				resp = make([]byte, loc[1]-loc[0])
+				copy(resp, in.buf[loc[0]:loc[1]])
+
+				in.remove(da[0], da[1])
+				in.remove(loc[0], loc[1])
+			} else {
+				in.remove(da[0], da[1])
+			}
+
+			return resp, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, errTimeout
+		}
+
+		if err := in.fill(remaining); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// makeRaw puts Stdin into raw mode if not already and returns a function to
+// restore its previous state.
+func (c *con) makeRaw() (restore func(), err error) {
+	if c.isRaw() {
+		return func() {}, nil
+	}
+
+	if err := c.EnableRawMode(); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		// nolint:errcheck
+		c.DisableRawMode()
+	}, nil
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package console
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// readInput reads from r into p. If r is a file, it waits for input to be
+// available before reading so the read can be canceled while waiting.
+func readInput(r io.Reader, p []byte, cancel <-chan struct{}) (int, error) {
+	f, ok := r.(*os.File)
+	if !ok {
+		return r.Read(p)
+	}
+
+	fds := []unix.PollFd{{Fd: int32(f.Fd()), Events: unix.POLLIN}}
+	for {
+		select {
+		case <-cancel:
+			return 0, errCanceled
+		default:
+		}
+
+		// Poll briefly so cancellation is checked periodically.
+		n, err := unix.Poll(fds, 50)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil || n > 0 {
+			return f.Read(p)
+		}
+	}
+}
+
+// canCancelInput gets whether reads from r by readInput can be canceled.
+func canCancelInput(r io.Reader) bool {
+	_, ok := r.(*os.File)
+	return ok
+}
@@ -0,0 +1,15 @@
+package console
+
+import (
+	"io"
+)
+
+// readInput reads from r into p. Reads cannot be canceled on Windows.
+func readInput(r io.Reader, p []byte, _ <-chan struct{}) (int, error) {
+	return r.Read(p)
+}
+
+// canCancelInput gets whether reads from r by readInput can be canceled.
+func canCancelInput(io.Reader) bool {
+	return false
+}
@@ -0,0 +1,4 @@
+// Package ptytest opens pseudo-terminals for end-to-end tests of a Console
+// against a genuine terminal with a size and line discipline. It is only
+// supported on Linux.
+package ptytest
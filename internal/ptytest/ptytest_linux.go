@@ -0,0 +1,118 @@
+package ptytest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// PTY is a pseudo-terminal for end-to-end tests. Attach a Console to the
+// terminal side returned by TTY, then write input to the PTY and read what the
+// Console rendered using Output.
+type PTY struct {
+	master *os.File
+	tty    *os.File
+
+	output bytes.Buffer
+	done   chan struct{}
+	lock   sync.Mutex
+}
+
+// Open opens a pseudo-terminal width columns wide and height rows high.
+func Open(width, height int) (*PTY, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("failed to unlock pseudo-terminal: %w", err)
+	}
+
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, fmt.Errorf("failed to get pseudo-terminal number: %w", err)
+	}
+
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	p := &PTY{
+		master: master,
+		tty:    tty,
+		done:   make(chan struct{}),
+	}
+
+	if err := p.Resize(width, height); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	go p.read()
+	return p, nil
+}
+
+// TTY gets the terminal side of the PTY to attach a Console to.
+func (p *PTY) TTY() *os.File {
+	return p.tty
+}
+
+// Write writes input e.g., keystrokes to the terminal.
+func (p *PTY) Write(b []byte) (n int, err error) {
+	return p.master.Write(b)
+}
+
+// Resize sets the size of the terminal as a terminal emulator would when its
+// window is resized.
+func (p *PTY) Resize(width, height int) error {
+	return unix.IoctlSetWinsize(int(p.master.Fd()), unix.TIOCSWINSZ, &unix.Winsize{
+		Col: uint16(width),
+		Row: uint16(height),
+	})
+}
+
+// Output gets everything written to the terminal so far.
+func (p *PTY) Output() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.output.String()
+}
+
+// Close closes both sides of the terminal.
+func (p *PTY) Close() error {
+	err := p.tty.Close()
+	if merr := p.master.Close(); err == nil {
+		err = merr
+	}
+
+	<-p.done
+	return err
+}
+
+// read records output from the terminal until the PTY is closed.
+func (p *PTY) read() {
+	defer close(p.done)
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := p.master.Read(buf)
+		if n > 0 {
+			p.lock.Lock()
+			p.output.Write(buf[:n])
+			p.lock.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
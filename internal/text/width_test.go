@@ -0,0 +1,55 @@
+package text
+
+import (
+	"testing"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "empty", s: "", want: 0},
+		{name: "ascii", s: "hello", want: 5},
+		{name: "latin", s: "héllo", want: 5},
+		{name: "combining", s: "he\u0301llo", want: 5},
+		{name: "cjk", s: "世界", want: 4},
+		{name: "hangul", s: "한국어", want: 6},
+		{name: "fullwidth", s: "ＡＢ", want: 4},
+		{name: "emoji", s: "👍", want: 2},
+		{name: "control", s: "a\tb", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Fatalf("StringWidth() = %d, expected %d", got, tt.want)
+			}
+			if got := RunesWidth([]rune(tt.s)); got != tt.want {
+				t.Fatalf("RunesWidth() = %d, expected %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{s: "hello", width: 10, want: "hello"},
+		{s: "hello", width: 3, want: "hel"},
+		{s: "世界", width: 3, want: "世"},
+		{s: "世界", width: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Truncate(tt.s, tt.width); got != tt.want {
+				t.Fatalf("Truncate() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package console
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// KeyboardEnhancement flags for the kitty keyboard protocol.
+// See https://sw.kovidgoyal.net/kitty/keyboard-protocol/.
+type KeyboardEnhancement int
+
+const (
+	// KeyboardDisambiguate reports keys using escape codes that would
+	// otherwise be ambiguous e.g., Ctrl+I and Tab, or Alt+[ and ESC [.
+	KeyboardDisambiguate KeyboardEnhancement = 1 << iota
+
+	// KeyboardReportEvents reports when keys are repeated and released.
+	KeyboardReportEvents
+
+	// KeyboardReportAlternateKeys reports the shifted key along with the key.
+	KeyboardReportAlternateKeys
+
+	// KeyboardReportAllKeys reports all keys, including Enter, Tab, and
+	// Backspace, using escape codes.
+	KeyboardReportAllKeys
+
+	// KeyboardReportText reports the text a key produces.
+	KeyboardReportText
+)
+
+// Progressive enhancement flags response.
+var keyboardResponse = regexp.MustCompile(`\x1b\[\?[0-9]*u`)
+
+// EnableKeyboardEnhancement queries whether the terminal supports the kitty
+// keyboard protocol and, if so, enables the given enhancements. It returns
+// false if the protocol is not supported, in which case keys continue to be
+// reported using legacy encodings.
+func (c *con) EnableKeyboardEnhancement(flags KeyboardEnhancement) bool {
+	resp, err := c.query(ansi.CSI+"?u", keyboardResponse)
+	if err != nil || resp == nil {
+		return false
+	}
+
+	c.modesLock.Lock()
+	defer c.modesLock.Unlock()
+
+	// Push the enhancements onto the terminal's stack.
+	fmt.Fprintf(c.out(), ansi.CSI+">%du", flags)
+	c.modes.keyboard = append(c.modes.keyboard, flags)
+
+	return true
+}
+
+// DisableKeyboardEnhancement restores keyboard enhancements to what they were
+// before the last call to EnableKeyboardEnhancement that returned true.
+func (c *con) DisableKeyboardEnhancement() {
+	c.modesLock.Lock()
+	defer c.modesLock.Unlock()
+
+	if n := len(c.modes.keyboard); n > 0 {
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "<u"))
+		c.modes.keyboard = c.modes.keyboard[:n-1]
+	}
+}
+
+func keyAction(event int) KeyAction {
+	switch event {
+	case 2:
+		return KeyRepeat
+	case 3:
+		return KeyRelease
+	}
+
+	return KeyPress
+}
+
+// Functional keys with codes other than their legacy control characters.
+var kittyKeys = map[int]Key{
+	9:     KeyTab,
+	13:    KeyEnter,
+	27:    KeyEscape,
+	127:   KeyBackspace,
+	57414: KeyEnter,
+	57417: KeyLeft,
+	57418: KeyRight,
+	57419: KeyUp,
+	57420: KeyDown,
+	57421: KeyPageUp,
+	57422: KeyPageDown,
+	57423: KeyHome,
+	57424: KeyEnd,
+	57425: KeyInsert,
+	57426: KeyDelete,
+}
+
+// Keypad keys that produce text.
+var kittyKeypadRunes = map[int]rune{
+	57399: '0',
+	57400: '1',
+	57401: '2',
+	57402: '3',
+	57403: '4',
+	57404: '5',
+	57405: '6',
+	57406: '7',
+	57407: '8',
+	57408: '9',
+	57409: '.',
+	57410: '/',
+	57411: '*',
+	57412: '-',
+	57413: '+',
+	57415: '=',
+}
+
+const (
+	// Start of the Unicode private use area where functional keys are encoded.
+	kittyPrivateUse = 57344
+)
+
+// decodeKittyKey decodes the arguments of CSI code[:shifted[:base]] ;
+// modifiers[:event] ; text u.
+func decodeKittyKey(args [][]int, mods Modifiers, action KeyAction) (KeyEvent, bool) {
+	if len(args[0]) == 0 {
+		return KeyEvent{}, false
+	}
+
+	code := args[0][0]
+	ev := KeyEvent{Modifiers: mods, Action: action}
+
+	if key, ok := kittyKeys[code]; ok {
+		ev.Key = key
+		return ev, true
+	}
+
+	if r, ok := kittyKeypadRunes[code]; ok {
+		ev.Rune = r
+		return ev, true
+	}
+
+	if code >= kittyPrivateUse && code <= 0xf8ff {
+		// Other functional keys e.g., modifier keys, are not supported.
+		return KeyEvent{}, false
+	}
+
+	ev.Rune = rune(code)
+	if mods&ModShift != 0 && len(args[0]) > 1 && args[0][1] > 0 {
+		ev.Rune = rune(args[0][1])
+	}
+
+	// Prefer text if reported as a single code point.
+	if len(args) > 2 && len(args[2]) == 1 && args[2][0] > 0 {
+		ev.Rune = rune(args[2][0])
+	}
+
+	return ev, true
+}
@@ -0,0 +1,141 @@
+package console
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestConsole_EnableKeyboardEnhancement(t *testing.T) {
+	f := Fake(
+		WithStdin(bytes.NewBufferString("\x1b[?0u\x1b[?62c")),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	if !f.EnableKeyboardEnhancement(KeyboardDisambiguate | KeyboardReportEvents) {
+		t.Fatal("EnableKeyboardEnhancement() = false, expected true")
+	}
+	f.Restore()
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[?u\x1b[c\x1b[>3u\x1b[<1u"; got != want {
+		t.Fatalf("EnableKeyboardEnhancement() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestConsole_EnableKeyboardEnhancement_unsupported(t *testing.T) {
+	f := Fake(
+		WithStdin(bytes.NewBufferString("\x1b[?62c")),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	if f.EnableKeyboardEnhancement(KeyboardDisambiguate) {
+		t.Fatal("EnableKeyboardEnhancement() = true, expected false")
+	}
+
+	f.DisableKeyboardEnhancement()
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[?u\x1b[c"; got != want {
+		t.Fatalf("EnableKeyboardEnhancement() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestConsole_ReadEvent_kitty(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  KeyEvent
+	}{
+		{
+			name:  "ctrl+i",
+			input: "\x1b[105;5u",
+			want:  KeyEvent{Key: KeyRune, Rune: 'i', Modifiers: ModCtrl},
+		},
+		{
+			name:  "tab",
+			input: "\x1b[9u",
+			want:  KeyEvent{Key: KeyTab},
+		},
+		{
+			name:  "escape",
+			input: "\x1b[27u",
+			want:  KeyEvent{Key: KeyEscape},
+		},
+		{
+			name:  "release",
+			input: "\x1b[97;1:3u",
+			want:  KeyEvent{Key: KeyRune, Rune: 'a', Action: KeyRelease},
+		},
+		{
+			name:  "repeat",
+			input: "\x1b[1;1:2A",
+			want:  KeyEvent{Key: KeyUp, Action: KeyRepeat},
+		},
+		{
+			name:  "shifted",
+			input: "\x1b[97:65;2u",
+			want:  KeyEvent{Key: KeyRune, Rune: 'A', Modifiers: ModShift},
+		},
+		{
+			name:  "text",
+			input: "\x1b[97;2;65u",
+			want:  KeyEvent{Key: KeyRune, Rune: 'A', Modifiers: ModShift},
+		},
+		{
+			name:  "keypad",
+			input: "\x1b[57400u",
+			want:  KeyEvent{Key: KeyRune, Rune: '1'},
+		},
+		{
+			name:  "keypad enter",
+			input: "\x1b[57414;3u",
+			want:  KeyEvent{Key: KeyEnter, Modifiers: ModAlt},
+		},
+		{
+			name:  "ignores lock modifiers",
+			input: "\x1b[13;65u",
+			want:  KeyEvent{Key: KeyEnter},
+		},
+		{
+			name:  "delete release",
+			input: "\x1b[3;1:3~",
+			want:  KeyEvent{Key: KeyDelete, Action: KeyRelease},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+			)
+
+			ev, err := f.ReadEvent()
+			if err != nil {
+				t.Fatalf("ReadEvent() error = %v", err)
+			}
+			if !reflect.DeepEqual(ev, tt.want) {
+				t.Fatalf("ReadEvent() = %+v, expected %+v", ev, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_ReadEvent_kittyUnsupported(t *testing.T) {
+	// Left shift is reported with KeyboardReportAllKeys but not supported.
+	f := Fake(
+		WithStdin(bytes.NewBufferString("\x1b[57441;2ux")),
+	)
+
+	ev, err := f.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent() error = %v", err)
+	}
+
+	want := KeyEvent{Key: KeyRune, Rune: 'x'}
+	if ev != want {
+		t.Fatalf("ReadEvent() = %+v, expected %+v", ev, want)
+	}
+}
@@ -0,0 +1,247 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/heaths/go-console/pkg/ansi"
+	"golang.org/x/term"
+)
+
+// modes tracks terminal modes changed through the console so they can be
+// restored.
+type modes struct {
+	altScreen    bool
+	cursorHidden bool
+	title        bool
+	scrollRegion bool
+	mouse        MouseTracking
+	paste        bool
+	focus        bool
+	keyboard     []KeyboardEnhancement
+
+	raw      bool
+	rawState *term.State
+}
+
+// EnableRawMode puts Stdin into raw mode, disabling line buffering, echo, and
+// signal processing by the terminal.
+func (c *con) EnableRawMode() error {
+	if !c.IsStdinTTY() {
+		return errNotTTY
+	}
+
+	c.modesLock.Lock()
+	defer c.modesLock.Unlock()
+
+	if c.modes.raw {
+		return nil
+	}
+
+	if f, ok := c.stdin.(*os.File); ok {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return err
+		}
+		c.modes.rawState = state
+	}
+
+	c.modes.raw = true
+	return nil
+}
+
+// DisableRawMode restores Stdin to the state it was in before EnableRawMode.
+func (c *con) DisableRawMode() error {
+	c.modesLock.Lock()
+	defer c.modesLock.Unlock()
+
+	return c.disableRawMode()
+}
+
+func (c *con) disableRawMode() error {
+	if !c.modes.raw {
+		return nil
+	}
+
+	if f, ok := c.stdin.(*os.File); ok && c.modes.rawState != nil {
+		if err := term.Restore(int(f.Fd()), c.modes.rawState); err != nil {
+			return err
+		}
+	}
+
+	c.modes.raw = false
+	c.modes.rawState = nil
+	return nil
+}
+
+func (c *con) isRaw() bool {
+	c.modesLock.Lock()
+	defer c.modesLock.Unlock()
+
+	return c.modes.raw
+}
+
+// Restore restores any terminal modes changed through the console e.g., leaves
+// the alternative screen buffer, shows the cursor, and disables raw mode. It
+// also writes any output held when escape sequences are removed.
+func (c *con) Restore() {
+	c.restore(true)
+
+	for _, w := range []io.Writer{c.stdout, c.stderr} {
+		if sw, ok := w.(*ansi.StripWriter); ok {
+			// nolint:errcheck
+			sw.Flush()
+		}
+	}
+}
+
+// suspend restores terminal modes and stops reading input so another program
+// can use the terminal, and returns a function to change them back. The title
+// and scroll region are not changed.
+func (c *con) suspend() (resume func()) {
+	c.inputLock.Lock()
+	in := c.input
+	c.inputLock.Unlock()
+
+	resumeInput := func() {}
+	if in != nil {
+		resumeInput = in.suspend()
+	}
+
+	c.modesLock.Lock()
+	saved := c.modes
+	saved.keyboard = append([]KeyboardEnhancement(nil), c.modes.keyboard...)
+	c.modesLock.Unlock()
+
+	c.restore(false)
+
+	return func() {
+		resumeInput()
+
+		if saved.raw {
+			// nolint:errcheck
+			c.EnableRawMode()
+		}
+		if saved.altScreen {
+			c.StartAlternativeScreenBuffer()
+		}
+		if saved.cursorHidden {
+			c.HideCursor()
+		}
+		if saved.mouse != 0 {
+			c.EnableMouse(saved.mouse)
+		}
+		if saved.paste {
+			c.EnableBracketedPaste()
+		}
+		if saved.focus {
+			c.EnableFocusReporting()
+		}
+
+		if len(saved.keyboard) > 0 {
+			c.modesLock.Lock()
+			defer c.modesLock.Unlock()
+
+			for _, flags := range saved.keyboard {
+				fmt.Fprintf(c.out(), ansi.CSI+">%du", flags)
+			}
+			c.modes.keyboard = saved.keyboard
+		}
+	}
+}
+
+// restore restores terminal modes, including the title and scroll region if
+// all is true.
+func (c *con) restore(all bool) {
+	c.modesLock.Lock()
+	defer c.modesLock.Unlock()
+
+	// Write any incomplete synchronized update before restoring modes.
+	c.frameLock.Lock()
+	c.endFrame()
+	c.frameLock.Unlock()
+
+	// Restore modes in the opposite order they would typically be changed.
+	var sb strings.Builder
+	if len(c.modes.keyboard) > 0 {
+		sb.WriteString(ansi.CSI + fmt.Sprintf("<%du", len(c.modes.keyboard)))
+		c.modes.keyboard = nil
+	}
+	if c.modes.focus {
+		sb.WriteString(ansi.CSI + "?1004l")
+		c.modes.focus = false
+	}
+	if c.modes.paste {
+		sb.WriteString(ansi.CSI + "?2004l")
+		c.modes.paste = false
+	}
+	if c.modes.mouse != 0 {
+		sb.WriteString(disableMouseSequence(c.modes.mouse))
+		c.modes.mouse = 0
+	}
+	if all && c.modes.scrollRegion {
+		sb.WriteString(ansi.CSI + "r")
+		c.modes.scrollRegion = false
+	}
+	if c.modes.cursorHidden {
+		sb.WriteString(ansi.CSI + "?25h")
+		c.modes.cursorHidden = false
+	}
+	if c.modes.altScreen {
+		sb.WriteString(ansi.CSI + "?1049l")
+		c.modes.altScreen = false
+	}
+	if all && c.modes.title {
+		sb.WriteString(ansi.CSI + "23;0t")
+		c.modes.title = false
+	}
+
+	if sb.Len() > 0 {
+		// nolint:errcheck
+		c.recorded(c.stdout).Write([]byte(sb.String()))
+	}
+
+	// nolint:errcheck
+	c.disableRawMode()
+}
+
+// EnableBracketedPaste enables reporting pasted text as a single PasteEvent
+// read by ReadEvent instead of individual key presses.
+func (c *con) EnableBracketedPaste() {
+	c.setMode(2004, true, &c.modes.paste)
+}
+
+// DisableBracketedPaste disables reporting pasted text as a PasteEvent.
+func (c *con) DisableBracketedPaste() {
+	c.setMode(2004, false, &c.modes.paste)
+}
+
+// EnableFocusReporting enables reporting a FocusEvent or BlurEvent read by
+// ReadEvent when the terminal gains or loses focus.
+func (c *con) EnableFocusReporting() {
+	c.setMode(1004, true, &c.modes.focus)
+}
+
+// DisableFocusReporting disables reporting when the terminal gains or loses
+// focus.
+func (c *con) DisableFocusReporting() {
+	c.setMode(1004, false, &c.modes.focus)
+}
+
+// setMode sets or resets a DEC private mode and records whether it is set.
+func (c *con) setMode(mode int, set bool, enabled *bool) {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		cmd := 'l'
+		if set {
+			cmd = 'h'
+		}
+
+		fmt.Fprintf(c.out(), ansi.CSI+"?%d%c", mode, cmd)
+		*enabled = set
+	}
+}
@@ -0,0 +1,21 @@
+package console
+
+import (
+	"testing"
+)
+
+func TestConsole_EnableBracketedPaste(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	f.EnableBracketedPaste()
+	f.EnableFocusReporting()
+	f.DisableFocusReporting()
+	f.Restore()
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[?2004h\x1b[?1004h\x1b[?1004l\x1b[?2004l"; got != want {
+		t.Fatalf("EnableBracketedPaste() wrote %q, expected %q", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// MouseTracking is which mouse events are reported.
+type MouseTracking int
+
+const (
+	// MouseTrackButtons reports button presses and releases, and the wheel.
+	MouseTrackButtons MouseTracking = iota + 1
+
+	// MouseTrackDrag also reports motion while a button is pressed.
+	MouseTrackDrag
+
+	// MouseTrackMotion also reports all motion even when no button is pressed.
+	MouseTrackMotion
+)
+
+var mouseTrackingModes = map[MouseTracking]int{
+	MouseTrackButtons: 1000,
+	MouseTrackDrag:    1002,
+	MouseTrackMotion:  1003,
+}
+
+// MouseButton is the button pressed or released, or the wheel scrolled.
+type MouseButton int
+
+const (
+	MouseNone MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+	MouseWheelLeft
+	MouseWheelRight
+)
+
+// MouseAction is what the mouse did.
+type MouseAction int
+
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	MouseMove
+)
+
+// MouseEvent is a mouse button pressed or released, the wheel scrolled, or the
+// mouse moved. See EnableMouse.
+type MouseEvent struct {
+	// Row is the 1-based row of the mouse.
+	Row int
+
+	// Column is the 1-based column of the mouse.
+	Column int
+
+	Button    MouseButton
+	Action    MouseAction
+	Modifiers Modifiers
+}
+
+func (MouseEvent) isEvent() {}
+
+// EnableMouse enables reporting mouse events read by ReadEvent using SGR
+// extended coordinates.
+func (c *con) EnableMouse(tracking MouseTracking) {
+	mode, ok := mouseTrackingModes[tracking]
+	if !ok {
+		panic("invalid mouse tracking")
+	}
+
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		s := disableMouseSequence(c.modes.mouse)
+		s += fmt.Sprintf(ansi.CSI+"?%dh"+ansi.CSI+"?1006h", mode)
+
+		// nolint:errcheck
+		c.out().Write([]byte(s))
+		c.modes.mouse = tracking
+	}
+}
+
+// DisableMouse disables reporting mouse events.
+func (c *con) DisableMouse() {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		if s := disableMouseSequence(c.modes.mouse); s != "" {
+			// nolint:errcheck
+			c.out().Write([]byte(s))
+		}
+		c.modes.mouse = 0
+	}
+}
+
+func disableMouseSequence(tracking MouseTracking) string {
+	if mode, ok := mouseTrackingModes[tracking]; ok {
+		return fmt.Sprintf(ansi.CSI+"?1006l"+ansi.CSI+"?%dl", mode)
+	}
+
+	return ""
+}
+
+// SGR mouse button bits.
+const (
+	mouseButtonMask = 0x03
+	mouseShift      = 0x04
+	mouseAlt        = 0x08
+	mouseCtrl       = 0x10
+	mouseMotion     = 0x20
+	mouseWheel      = 0x40
+)
+
+// decodeSGRMouse decodes the parameters of an SGR mouse report
+// e.g., ESC [ < button ; column ; row M.
+func decodeSGRMouse(params string, final byte) (MouseEvent, bool) {
+	parts := strings.Split(params, ";")
+	if len(parts) != 3 {
+		return MouseEvent{}, false
+	}
+
+	var args [3]int
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return MouseEvent{}, false
+		}
+		args[i] = v
+	}
+
+	cb := args[0]
+	ev := MouseEvent{
+		Column: args[1],
+		Row:    args[2],
+		Action: MousePress,
+	}
+
+	if cb&mouseShift != 0 {
+		ev.Modifiers |= ModShift
+	}
+	if cb&mouseAlt != 0 {
+		ev.Modifiers |= ModAlt
+	}
+	if cb&mouseCtrl != 0 {
+		ev.Modifiers |= ModCtrl
+	}
+
+	button := cb & mouseButtonMask
+	switch {
+	case cb&mouseWheel != 0:
+		ev.Button = MouseWheelUp + MouseButton(button)
+	case button == 3:
+		ev.Button = MouseNone
+	default:
+		ev.Button = MouseLeft + MouseButton(button)
+	}
+
+	switch {
+	case cb&mouseMotion != 0:
+		ev.Action = MouseMove
+	case final == 'm':
+		ev.Action = MouseRelease
+	}
+
+	return ev, true
+}
+
+// encodeSGRMouse encodes a MouseEvent as an SGR mouse report.
+func encodeSGRMouse(ev MouseEvent) string {
+	var cb int
+	switch {
+	case ev.Button >= MouseWheelUp:
+		cb = mouseWheel | int(ev.Button-MouseWheelUp)
+	case ev.Button == MouseNone:
+		cb = 3
+	default:
+		cb = int(ev.Button - MouseLeft)
+	}
+
+	if ev.Modifiers&ModShift != 0 {
+		cb |= mouseShift
+	}
+	if ev.Modifiers&ModAlt != 0 {
+		cb |= mouseAlt
+	}
+	if ev.Modifiers&ModCtrl != 0 {
+		cb |= mouseCtrl
+	}
+
+	final := 'M'
+	switch ev.Action {
+	case MouseMove:
+		cb |= mouseMotion
+	case MouseRelease:
+		final = 'm'
+	}
+
+	return fmt.Sprintf(ansi.CSI+"<%d;%d;%d%c", cb, ev.Column, ev.Row, final)
+}
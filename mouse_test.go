@@ -0,0 +1,111 @@
+package console
+
+import (
+	"testing"
+)
+
+func TestConsole_EnableMouse(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	f.EnableMouse(MouseTrackButtons)
+	f.EnableMouse(MouseTrackMotion)
+	f.DisableMouse()
+	f.EnableMouse(MouseTrackDrag)
+	f.Restore()
+
+	stdout, _, _ := f.Buffers()
+	want := "\x1b[?1000h\x1b[?1006h" +
+		"\x1b[?1006l\x1b[?1000l\x1b[?1003h\x1b[?1006h" +
+		"\x1b[?1006l\x1b[?1003l" +
+		"\x1b[?1002h\x1b[?1006h" +
+		"\x1b[?1006l\x1b[?1002l"
+	if got := stdout.String(); got != want {
+		t.Fatalf("EnableMouse() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestDecodeSGRMouse(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		final  byte
+		want   MouseEvent
+		wantOk bool
+	}{
+		{
+			name:   "left press",
+			params: "0;10;5",
+			final:  'M',
+			want:   MouseEvent{Row: 5, Column: 10, Button: MouseLeft, Action: MousePress},
+			wantOk: true,
+		},
+		{
+			name:   "right release",
+			params: "2;1;1",
+			final:  'm',
+			want:   MouseEvent{Row: 1, Column: 1, Button: MouseRight, Action: MouseRelease},
+			wantOk: true,
+		},
+		{
+			name:   "ctrl wheel down",
+			params: "81;300;200",
+			final:  'M',
+			want:   MouseEvent{Row: 200, Column: 300, Button: MouseWheelDown, Action: MousePress, Modifiers: ModCtrl},
+			wantOk: true,
+		},
+		{
+			name:   "drag middle",
+			params: "33;2;3",
+			final:  'M',
+			want:   MouseEvent{Row: 3, Column: 2, Button: MouseMiddle, Action: MouseMove},
+			wantOk: true,
+		},
+		{
+			name:   "motion shift alt",
+			params: "47;2;3",
+			final:  'M',
+			want:   MouseEvent{Row: 3, Column: 2, Button: MouseNone, Action: MouseMove, Modifiers: ModShift | ModAlt},
+			wantOk: true,
+		},
+		{
+			name:   "invalid",
+			params: "0;1",
+			final:  'M',
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := decodeSGRMouse(tt.params, tt.final)
+			if ok != tt.wantOk {
+				t.Fatalf("decodeSGRMouse() ok = %v, expected %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Fatalf("decodeSGRMouse() = %+v, expected %+v", got, tt.want)
+			}
+
+			if ok {
+				if s := encodeSGRMouse(got); s != "\x1b[<"+tt.params+string(tt.final) {
+					t.Fatalf("encodeSGRMouse() = %q, expected round trip", s)
+				}
+			}
+		})
+	}
+}
+
+func TestFakeConsole_SendMouse(t *testing.T) {
+	f := Fake()
+
+	want := MouseEvent{Row: 4, Column: 2, Button: MouseLeft, Action: MouseRelease, Modifiers: ModCtrl}
+	f.SendMouse(want)
+
+	ev, err := f.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent() error = %v", err)
+	}
+	if ev != want {
+		t.Fatalf("ReadEvent() = %+v, expected %+v", ev, want)
+	}
+}
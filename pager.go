@@ -0,0 +1,163 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// defaultPager is the pager command used if $PAGER is not set.
+const defaultPager = "less -FRX"
+
+// ErrPagerClosed is returned when writing to Stdout after the user quit the
+// pager. Callers may stop writing output.
+var ErrPagerClosed = errors.New("pager closed")
+
+// pagerWriter writes to the standard input of a pager.
+type pagerWriter struct {
+	w    io.WriteCloser
+	wait func() error
+
+	closed bool
+	lock   sync.Mutex
+}
+
+func (pw *pagerWriter) Write(p []byte) (n int, err error) {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	if pw.closed {
+		return 0, ErrPagerClosed
+	}
+
+	n, err = pw.w.Write(p)
+	if err != nil && (errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed)) {
+		// The user quit the pager before reading all output.
+		pw.closed = true
+		return n, ErrPagerClosed
+	}
+
+	return n, err
+}
+
+// Close closes the pager's standard input and waits for it to exit.
+func (pw *pagerWriter) Close() error {
+	pw.lock.Lock()
+	pw.closed = true
+	pw.lock.Unlock()
+
+	// nolint:errcheck
+	pw.w.Close()
+	return pw.wait()
+}
+
+// StartPager starts the pager command in $PAGER, or "less -FRX" by default,
+// and writes to its standard input anything written to Stdout until StopPager
+// is called. Stdout is still considered a terminal, so color output is
+// preserved. Writes return ErrPagerClosed after the user quits the pager.
+//
+// If the pager command is not found, output is buffered and shown by a
+// built-in pager when StopPager is called. It can be scrolled using the arrow
+// keys, Space, b, g, and G, and searched using /, n, and N. Press q to quit.
+//
+// Nothing is done if Stdout is not a terminal, $PAGER is empty or "cat", or a
+// pager is already started.
+func (c *con) StartPager() error {
+	if !c.IsStdoutTTY() {
+		return nil
+	}
+
+	c.frameLock.Lock()
+	defer c.frameLock.Unlock()
+
+	if c.pager != nil {
+		return nil
+	}
+
+	if c.fakePager != nil {
+		*c.fakePager = true
+		c.endFrame()
+		c.pager = &pagerWriter{
+			w:    nopWriteCloser{c.recorded(c.stdout)},
+			wait: func() error { return nil },
+		}
+		return nil
+	}
+
+	command, ok := os.LookupEnv("PAGER")
+	if !ok {
+		command = defaultPager
+	}
+
+	args := strings.Fields(command)
+	if len(args) == 0 || command == "cat" {
+		return nil
+	}
+
+	// Use the built-in pager if the pager command is not found.
+	if _, err := exec.LookPath(args[0]); err != nil {
+		c.endFrame()
+
+		var buf bytes.Buffer
+		c.pager = &pagerWriter{
+			w: nopWriteCloser{&buf},
+			wait: func() error {
+				return c.page(buf.Bytes())
+			},
+		}
+		return nil
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	// Flush output written before the pager was started.
+	c.endFrame()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start pager: %w", err)
+	}
+
+	c.pager = &pagerWriter{
+		w:    w,
+		wait: cmd.Wait,
+	}
+
+	return nil
+}
+
+// StopPager closes the pager's standard input and waits for the user to quit
+// the pager. Stdout is written to directly again.
+func (c *con) StopPager() error {
+	c.frameLock.Lock()
+	c.endFrame()
+	pager := c.pager
+	c.pager = nil
+	c.frameLock.Unlock()
+
+	if pager == nil {
+		return nil
+	}
+
+	return pager.Close()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConsole_StartPager(t *testing.T) {
+	tests := []struct {
+		name      string
+		tty       bool
+		wantPaged bool
+	}{
+		{name: "tty", tty: true, wantPaged: true},
+		{name: "not tty", tty: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paged bool
+			f := Fake(
+				WithStdoutTTY(tt.tty),
+				WithPager(&paged),
+			)
+
+			if err := f.StartPager(); err != nil {
+				t.Fatalf("StartPager() error = %v", err)
+			}
+			fmt.Fprint(f.Stdout(), "paged")
+			if err := f.StopPager(); err != nil {
+				t.Fatalf("StopPager() error = %v", err)
+			}
+
+			if paged != tt.wantPaged {
+				t.Fatalf("StartPager() paged = %v, expected %v", paged, tt.wantPaged)
+			}
+
+			stdout, _, _ := f.Buffers()
+			if got := stdout.String(); got != "paged" {
+				t.Fatalf("Stdout() = %q, expected %q", got, "paged")
+			}
+		})
+	}
+}
+
+func TestConsole_StartPager_command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires tr")
+	}
+
+	tests := []struct {
+		name  string
+		pager string
+		want  string
+	}{
+		{name: "pager", pager: "tr a-z A-Z", want: "BEFORE PAGED\nAFTER"},
+		{name: "cat", pager: "cat", want: "before paged\nafter"},
+		{name: "empty", pager: "", want: "before paged\nafter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", tt.pager)
+			f := Fake(WithStdoutTTY(true))
+
+			f.BeginSynchronizedUpdate()
+			fmt.Fprint(f.Stdout(), "before ")
+			if err := f.StartPager(); err != nil {
+				t.Fatalf("StartPager() error = %v", err)
+			}
+			f.EndSynchronizedUpdate()
+
+			fmt.Fprintln(f.Stdout(), "paged")
+			if err := f.StopPager(); err != nil {
+				t.Fatalf("StopPager() error = %v", err)
+			}
+			fmt.Fprint(f.Stdout(), "after")
+
+			stdout, _, _ := f.Buffers()
+			if got := stdout.String(); !strings.EqualFold(got, tt.want) {
+				t.Fatalf("Stdout() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPagerWriter_closed(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	pw := &pagerWriter{
+		w:    w,
+		wait: func() error { return nil },
+	}
+
+	if _, err := pw.Write([]byte("lost")); !errors.Is(err, ErrPagerClosed) {
+		t.Fatalf("Write() error = %v, expected %v", err, ErrPagerClosed)
+	}
+	if _, err := pw.Write([]byte("lost")); !errors.Is(err, ErrPagerClosed) {
+		t.Fatalf("Write() error = %v, expected %v", err, ErrPagerClosed)
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,433 @@
+package console
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/heaths/go-console/internal/text"
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// Names of Theme styles used by Pick. If not defined by the Themes of the
+// ColorScheme, default styles are used.
+const (
+	// ThemePickCursor styles the marker beside the current item.
+	ThemePickCursor = "pick.cursor"
+
+	// ThemePickMatch styles characters that match the query.
+	ThemePickMatch = "pick.match"
+
+	// ThemePickSelected styles the marker beside selected items.
+	ThemePickSelected = "pick.selected"
+)
+
+// minPreviewWidth is the narrowest console on which WithPreview shows a pane.
+const minPreviewWidth = 20
+
+// PickOption configures Pick.
+type PickOption func(*picker)
+
+// WithMultiSelect allows selecting multiple items by pressing Tab.
+func WithMultiSelect() PickOption {
+	return func(p *picker) {
+		p.multi = true
+	}
+}
+
+// WithPreview shows the text returned by fn for the current item in a pane
+// beside the list. The pane is not shown if the console is too narrow.
+func WithPreview(fn func(item string) string) PickOption {
+	return func(p *picker) {
+		p.preview = fn
+	}
+}
+
+type picker struct {
+	c       *con
+	prompt  string
+	items   []string
+	runes   [][]rune
+	multi   bool
+	preview func(string) string
+
+	query    []rune
+	results  []fuzzyResult
+	cursor   int
+	offset   int
+	selected map[int]bool
+
+	width int
+	rows  int
+	alt   bool
+}
+
+// Pick writes the prompt and lets the user type to fuzzily filter items, and
+// select an item using the arrow keys and Enter. If WithMultiSelect is
+// specified, multiple items can be selected by pressing Tab. Short lists are
+// rendered below the prompt, while longer lists use the alternative screen
+// buffer.
+//
+// If Stdin or Stdout is not a terminal, a line is read from Stdin containing
+// the 1-based index or exact value of an item, or a comma-separated list of
+// either if WithMultiSelect is specified.
+//
+// Pick returns the indices of selected items in the order they appear in
+// items, or ErrInterrupted if the user pressed Escape or Ctrl+C.
+func (c *con) Pick(prompt string, items []string, opts ...PickOption) ([]int, error) {
+	p := &picker{
+		c:        c,
+		prompt:   prompt,
+		items:    items,
+		runes:    make([][]rune, len(items)),
+		selected: make(map[int]bool),
+	}
+
+	for i, item := range items {
+		p.runes[i] = []rune(item)
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if c.IsStdinTTY() && c.IsStdoutTTY() {
+		return p.run()
+	}
+
+	return p.choose()
+}
+
+// choose reads the selection from Stdin.
+func (p *picker) choose() ([]int, error) {
+	if p.c.IsStdoutTTY() {
+		fmt.Fprint(p.c.out(), p.prompt)
+	}
+
+	line, err := p.c.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSpace(line)
+	if i := p.find(line); i >= 0 {
+		return []int{i}, nil
+	}
+
+	tokens := []string{line}
+	if p.multi {
+		tokens = strings.Split(line, ",")
+	}
+
+	var indices []int
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+
+		i := p.find(token)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid selection %q", token)
+		}
+		indices = append(indices, i)
+	}
+
+	return indices, nil
+}
+
+// find gets the index of the item equal to s or with the 1-based index s.
+func (p *picker) find(s string) int {
+	for i, item := range p.items {
+		if item == s {
+			return i
+		}
+	}
+
+	if i, err := strconv.Atoi(s); err == nil && i >= 1 && i <= len(p.items) {
+		return i - 1
+	}
+
+	return -1
+}
+
+func (p *picker) run() ([]int, error) {
+	c := p.c
+
+	width, height, err := c.Size()
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+	p.width = width
+
+	restore, err := c.makeRaw()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	// Render short lists inline, and longer lists using the entire screen.
+	p.rows = len(p.items)
+	if p.rows < 1 {
+		p.rows = 1
+	}
+
+	if p.rows+1 > height/2 {
+		p.alt = true
+		p.rows = height - 1
+
+		c.StartAlternativeScreenBuffer()
+	} else {
+		// Make room for the list.
+		fmt.Fprint(c.out(), strings.Repeat("\r\n", p.rows))
+		c.CursorUp(p.rows)
+	}
+
+	p.filter()
+	p.render()
+
+	for {
+		ev, err := c.ReadEvent()
+		if err != nil {
+			p.clear()
+			return nil, err
+		}
+
+		switch ev := ev.(type) {
+		case PasteEvent:
+			p.query = append(p.query, []rune(strings.ReplaceAll(ev.Text, "\n", " "))...)
+			p.filter()
+
+		case KeyEvent:
+			if ev.Action == KeyRelease {
+				continue
+			}
+
+			done, err := p.handleKey(ev)
+			if err != nil {
+				p.clear()
+				return nil, err
+			}
+			if done {
+				indices := p.selection()
+				p.clear()
+				p.summarize(indices)
+				return indices, nil
+			}
+		}
+
+		p.render()
+	}
+}
+
+// handleKey handles a key and returns true if the selection was accepted.
+func (p *picker) handleKey(ev KeyEvent) (bool, error) {
+	ctrl := ev.Modifiers&ModCtrl != 0
+
+	switch {
+	case ev.Key == KeyEnter:
+		return len(p.results) > 0, nil
+
+	case ev.Key == KeyEscape, ctrl && (ev.Rune == 'c' || ev.Rune == 'g'):
+		return false, ErrInterrupted
+
+	case ev.Key == KeyUp, ctrl && ev.Rune == 'p':
+		p.move(-1)
+
+	case ev.Key == KeyDown, ctrl && ev.Rune == 'n':
+		p.move(1)
+
+	case ev.Key == KeyPageUp:
+		p.move(-p.rows)
+
+	case ev.Key == KeyPageDown:
+		p.move(p.rows)
+
+	case ev.Key == KeyTab:
+		if p.multi && len(p.results) > 0 {
+			i := p.results[p.cursor].index
+			p.selected[i] = !p.selected[i]
+
+			if ev.Modifiers&ModShift != 0 {
+				p.move(-1)
+			} else {
+				p.move(1)
+			}
+		}
+
+	case ev.Key == KeyBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+
+	case ctrl && ev.Rune == 'u':
+		p.query = nil
+		p.filter()
+
+	case ctrl && ev.Rune == 'w':
+		i := len(p.query)
+		for i > 0 && p.query[i-1] == ' ' {
+			i--
+		}
+		for i > 0 && p.query[i-1] != ' ' {
+			i--
+		}
+		p.query = p.query[:i]
+		p.filter()
+
+	case ev.Key == KeyRune && ev.Modifiers&(ModCtrl|ModAlt|ModMeta) == 0:
+		p.query = append(p.query, ev.Rune)
+		p.filter()
+	}
+
+	return false, nil
+}
+
+func (p *picker) filter() {
+	p.results = fuzzyFilter(p.query, p.runes)
+	p.cursor = 0
+	p.offset = 0
+}
+
+func (p *picker) move(n int) {
+	if len(p.results) == 0 {
+		return
+	}
+
+	p.cursor = clamp(p.cursor+n, 0, len(p.results)-1)
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+p.rows {
+		p.offset = p.cursor - p.rows + 1
+	}
+}
+
+// selection gets the selected indices, or the current item if none selected.
+func (p *picker) selection() []int {
+	var indices []int
+	for i, selected := range p.selected {
+		if selected {
+			indices = append(indices, i)
+		}
+	}
+
+	if len(indices) == 0 {
+		return []int{p.results[p.cursor].index}
+	}
+
+	sort.Ints(indices)
+	return indices
+}
+
+func (p *picker) render() {
+	c := p.c
+	cs := c.ColorScheme()
+
+	c.SynchronizedUpdate(func() {
+		if p.alt {
+			c.MoveCursor(1, 1)
+		} else {
+			c.CursorColumn(1)
+		}
+
+		c.ClearLine()
+		fmt.Fprint(c.out(), p.prompt+string(p.query)+cs.LightBlack(fmt.Sprintf("  %d/%d", len(p.results), len(p.items))))
+
+		listWidth := p.width
+		showPreview := p.preview != nil && p.width >= minPreviewWidth
+		var preview []string
+		if showPreview {
+			listWidth = p.width / 2
+			if len(p.results) > 0 {
+				preview = strings.Split(p.preview(p.items[p.results[p.cursor].index]), "\n")
+			}
+		}
+
+		for row := 0; row < p.rows; row++ {
+			fmt.Fprint(c.out(), "\r\n")
+			c.ClearLine()
+
+			line, w := "", 0
+			if i := p.offset + row; i < len(p.results) {
+				line, w = p.line(p.results[i], i == p.cursor, listWidth)
+			}
+
+			if showPreview {
+				if w < listWidth {
+					line += strings.Repeat(" ", listWidth-w)
+				}
+				line += cs.LightBlack("│") + " "
+				if row < len(preview) {
+					line += text.Truncate(preview[row], p.width-listWidth-2)
+				}
+			}
+
+			fmt.Fprint(c.out(), line)
+		}
+
+		c.CursorUp(p.rows)
+		c.CursorColumn(ansi.StringWidth(p.prompt+string(p.query)) + 1)
+	})
+}
+
+// line formats a result within width columns and returns its visible width.
+func (p *picker) line(result fuzzyResult, current bool, width int) (string, int) {
+	cs := p.c.ColorScheme()
+	highlight := cs.ThemeFuncOr(ThemePickMatch, "cyan+b")
+
+	var sb strings.Builder
+	if current {
+		sb.WriteString(cs.ThemeFuncOr(ThemePickCursor, "red+b")(">"))
+	} else {
+		sb.WriteByte(' ')
+	}
+
+	w := 1
+	if p.multi {
+		if p.selected[result.index] {
+			sb.WriteString(cs.ThemeFuncOr(ThemePickSelected, "magenta")("*"))
+		} else {
+			sb.WriteByte(' ')
+		}
+		w++
+	}
+	sb.WriteByte(' ')
+	w++
+
+	positions := result.positions
+	for i, r := range p.runes[result.index] {
+		rw := text.RuneWidth(r)
+		if w+rw > width {
+			break
+		}
+		w += rw
+
+		if len(positions) > 0 && positions[0] == i {
+			sb.WriteString(highlight(string(r)))
+			positions = positions[1:]
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String(), w
+}
+
+// clear clears the list, leaving the cursor at the start of the prompt.
+func (p *picker) clear() {
+	if p.alt {
+		p.c.StopAlternativeScreenBuffer()
+		return
+	}
+
+	p.c.CursorColumn(1)
+	p.c.ClearLinesDown(p.rows + 1)
+}
+
+func (p *picker) summarize(indices []int) {
+	values := make([]string, len(indices))
+	for i, index := range indices {
+		values[i] = p.items[index]
+	}
+
+	fmt.Fprint(p.c.out(), p.prompt+strings.Join(values, ", ")+"\r\n")
+}
@@ -0,0 +1,280 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/ansi"
+	"github.com/heaths/go-console/pkg/colorscheme"
+)
+
+func TestConsole_Pick(t *testing.T) {
+	items := []string{"apple", "banana", "cherry", "date"}
+
+	tests := []struct {
+		name    string
+		input   string
+		opts    []PickOption
+		want    []int
+		wantErr error
+		screen  string
+	}{
+		{
+			name:   "first",
+			input:  "\r",
+			want:   []int{0},
+			screen: "? apple",
+		},
+		{
+			name:   "move",
+			input:  "\x1b[B\x1b[B\x1b[A\x0e\r",
+			want:   []int{2},
+			screen: "? cherry",
+		},
+		{
+			name:   "filter",
+			input:  "an\r",
+			want:   []int{1},
+			screen: "? banana",
+		},
+		{
+			name:   "backspace",
+			input:  "bx\x7f\x7fch\r",
+			want:   []int{2},
+			screen: "? cherry",
+		},
+		{
+			name:  "no match",
+			input: "xyz\r\x15\r",
+			want:  []int{0},
+		},
+		{
+			name:   "multi-select",
+			input:  "\t\x1b[B\t\r",
+			opts:   []PickOption{WithMultiSelect()},
+			want:   []int{0, 2},
+			screen: "? apple, cherry",
+		},
+		{
+			name:  "toggle",
+			input: "\t\x1b[A\t\r",
+			opts:  []PickOption{WithMultiSelect()},
+			want:  []int{1},
+		},
+		{
+			name:  "toggle up",
+			input: "\x1b[B\t\x1b[Z\r",
+			opts:  []PickOption{WithMultiSelect()},
+			want:  []int{1, 2},
+		},
+		{
+			name:    "escape",
+			input:   "a\x1b",
+			wantErr: ErrInterrupted,
+		},
+		{
+			name:    "interrupt",
+			input:   "\x03",
+			wantErr: ErrInterrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+				WithSize(40, 12),
+			)
+
+			got, err := f.Pick("? ", items, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Pick() error = %v, expected %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Pick() = %v, expected %v", got, tt.want)
+			}
+
+			lines := f.Screen().Lines()
+			if tt.screen != "" && lines[0] != tt.screen {
+				t.Fatalf("Screen() = %q, expected %q", lines[0], tt.screen)
+			}
+			for i := 1; i < len(lines); i++ {
+				if lines[i] != "" {
+					t.Fatalf("Screen() line %d = %q, expected cleared", i+1, lines[i])
+				}
+			}
+
+			if f.isRaw() {
+				t.Fatal("Pick() did not disable raw mode")
+			}
+		})
+	}
+}
+
+func TestConsole_Pick_render(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+		WithSize(40, 12),
+	)
+
+	p := &picker{
+		c:        f.con,
+		prompt:   "? ",
+		items:    []string{"one", "two", "three"},
+		runes:    [][]rune{[]rune("one"), []rune("two"), []rune("three")},
+		multi:    true,
+		preview:  strings.ToUpper,
+		selected: map[int]bool{2: true},
+		width:    40,
+		rows:     3,
+	}
+	p.query = []rune("e")
+	p.filter()
+	p.render()
+
+	want := []string{
+		"? e  2/3",
+		">  one              │ ONE",
+		" * three            │",
+		"                    │",
+	}
+	screen := f.Screen()
+	if got := screen.Lines()[:4]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Screen() = %q, expected %q", got, want)
+	}
+	if row, col := screen.Cursor(); row != 1 || col != 4 {
+		t.Fatalf("Screen().Cursor() = (%d, %d), expected (1, 4)", row, col)
+	}
+}
+
+func TestConsole_Pick_render_narrow(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+		WithSize(4, 12),
+	)
+
+	p := &picker{
+		c:        f.con,
+		prompt:   "? ",
+		items:    []string{"one", "two"},
+		runes:    [][]rune{[]rune("one"), []rune("two")},
+		preview:  strings.ToUpper,
+		selected: map[int]bool{},
+		width:    4,
+		rows:     2,
+	}
+	p.filter()
+	p.render()
+
+	// The preview is not shown when too narrow.
+	for _, line := range f.Screen().Lines()[:3] {
+		if strings.Contains(line, "│") {
+			t.Fatalf("Screen() = %q, expected no preview", f.Screen().Lines())
+		}
+	}
+}
+
+func TestConsole_Pick_render_colors(t *testing.T) {
+	theme := colorscheme.Theme{ThemePickCursor: "green"}
+	f := Fake(
+		WithStdoutTTY(true),
+		WithSize(40, 12),
+	)
+	f.cs = colorscheme.New(
+		colorscheme.WithTTY(f.IsStdoutTTY),
+		colorscheme.WithThemes(theme, theme),
+	)
+
+	p := &picker{
+		c:        f.con,
+		prompt:   f.cs.Green("?") + " ",
+		items:    []string{"one", "two"},
+		runes:    [][]rune{[]rune("one"), []rune("two")},
+		selected: map[int]bool{},
+		width:    40,
+		rows:     2,
+	}
+	p.query = []rune("o")
+	p.filter()
+	p.render()
+
+	screen := f.Screen()
+	if row, col := screen.Cursor(); row != 1 || col != 4 {
+		t.Fatalf("Screen().Cursor() = (%d, %d), expected (1, 4)", row, col)
+	}
+
+	// The cursor is styled by the theme and matches by default.
+	if got, want := screen.Cell(2, 1).Style.Foreground, ansi.IndexedColor(2); got != want {
+		t.Fatalf("cursor color = %v, expected %v", got, want)
+	}
+	if got, want := screen.Cell(2, 3).Style.Foreground, ansi.IndexedColor(6); got != want {
+		t.Fatalf("match color = %v, expected %v", got, want)
+	}
+}
+
+func TestConsole_Pick_alternativeScreenBuffer(t *testing.T) {
+	items := make([]string, 20)
+	for i := range items {
+		items[i] = strings.Repeat("x", i+1)
+	}
+
+	f := Fake(
+		WithStdin(bytes.NewBufferString("\x1b[6~\r")),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+		WithSize(40, 10),
+	)
+
+	got, err := f.Pick("? ", items)
+	if err != nil {
+		t.Fatalf("Pick() error = %v", err)
+	}
+	if want := []int{9}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Pick() = %v, expected %v", got, want)
+	}
+
+	screen := f.Screen()
+	if screen.AlternativeScreenBuffer() {
+		t.Fatal("Pick() did not stop the alternative screen buffer")
+	}
+	if want := "? xxxxxxxxxx"; screen.Lines()[0] != want {
+		t.Fatalf("Screen() = %q, expected %q", screen.Lines()[0], want)
+	}
+}
+
+func TestConsole_Pick_notTTY(t *testing.T) {
+	items := []string{"apple", "banana", "cherry"}
+
+	tests := []struct {
+		name    string
+		input   string
+		opts    []PickOption
+		want    []int
+		wantErr bool
+	}{
+		{name: "value", input: "banana\n", want: []int{1}},
+		{name: "index", input: "3\n", want: []int{2}},
+		{name: "multiple", input: "apple, 3\n", opts: []PickOption{WithMultiSelect()}, want: []int{0, 2}},
+		{name: "multiple not allowed", input: "apple,3\n", wantErr: true},
+		{name: "out of range", input: "4\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(WithStdin(bytes.NewBufferString(tt.input)))
+
+			got, err := f.Pick("? ", items, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Pick() error = %v, expected error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Pick() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
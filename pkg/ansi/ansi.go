@@ -0,0 +1,15 @@
+// Package ansi defines common ANSI escape sequences and parses text written
+// with escape sequences e.g., to remove them.
+package ansi
+
+const (
+	ESC = "\x1b"
+	CSI = ESC + "["
+	OSC = ESC + "]"
+	DCS = ESC + "P"
+	ST  = ESC + "\\"
+	BEL = "\a"
+
+	// Select graphics rendition (SGR) codes.
+	Reset = CSI + "0m"
+)
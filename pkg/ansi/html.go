@@ -0,0 +1,320 @@
+package ansi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// HTMLOption configures an HTMLWriter.
+type HTMLOption func(*HTMLWriter)
+
+// WithClasses sets CSS classes starting with prefix e.g., "ansi-bold" or
+// "ansi-red" instead of inline styles. 256 and RGB colors are still set using
+// inline styles, as are default colors for inverse text. Inverse text swaps
+// the foreground and background color classes. See Stylesheet.
+func WithClasses(prefix string) HTMLOption {
+	return func(w *HTMLWriter) {
+		w.classes = true
+		w.prefix = prefix
+	}
+}
+
+// WithDefaultColors sets the colors in "#rrggbb" or any other CSS format used
+// for inverse text when the foreground or background color is the default.
+// The default colors are "#e5e5e5" and "#000000".
+func WithDefaultColors(foreground, background string) HTMLOption {
+	return func(w *HTMLWriter) {
+		w.foreground = foreground
+		w.background = background
+	}
+}
+
+// HTMLWriter converts text with SGR and OSC 8 hyperlink escape sequences
+// written to it to HTML written to an underlying Writer. Text is escaped and
+// styled using <span> elements, and hyperlinks use <a> elements. Other escape
+// sequences and control characters except "\n" and "\t" are removed.
+// Write the HTML within a <pre> element to preserve whitespace.
+type HTMLWriter struct {
+	w io.Writer
+	t Tokenizer
+
+	classes    bool
+	prefix     string
+	foreground string
+	background string
+
+	style    Style
+	open     bool
+	link     string
+	linkOpen bool
+
+	buf bytes.Buffer
+}
+
+// NewHTMLWriter gets an HTMLWriter that writes HTML to w. Call Close to close
+// any open elements.
+func NewHTMLWriter(w io.Writer, opts ...HTMLOption) *HTMLWriter {
+	hw := &HTMLWriter{
+		w:          w,
+		foreground: "#e5e5e5",
+		background: "#000000",
+	}
+
+	for _, opt := range opts {
+		opt(hw)
+	}
+
+	return hw
+}
+
+// HTML converts s to HTML. See HTMLWriter.
+func HTML(s string, opts ...HTMLOption) string {
+	var sb strings.Builder
+	w := NewHTMLWriter(&sb, opts...)
+
+	// nolint:errcheck
+	io.WriteString(w, s)
+	// nolint:errcheck
+	w.Close()
+
+	return sb.String()
+}
+
+// Write converts p to HTML. Escape sequences may span writes.
+func (w *HTMLWriter) Write(p []byte) (n int, err error) {
+	w.buf.Reset()
+	w.t.Tokenize(p, w.token)
+
+	if w.buf.Len() > 0 {
+		if _, err := w.w.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close closes any open elements. It does not close the underlying Writer.
+func (w *HTMLWriter) Close() error {
+	w.buf.Reset()
+	w.t.Flush(w.token)
+	w.closeSpan()
+	w.closeLink()
+
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
+
+func (w *HTMLWriter) token(tok Token) {
+	switch tok.Kind {
+	case TokenText:
+		w.writeText(tok.Raw)
+
+	case TokenControl:
+		if tok.Final == '\n' || tok.Final == '\t' {
+			w.writeText(tok.Raw)
+		}
+
+	case TokenCSI:
+		if tok.Final != 'm' || tok.Private != 0 || len(tok.Intermediates) > 0 {
+			return
+		}
+
+		if style := w.style.SGR(string(tok.Params)); style != w.style {
+			w.closeSpan()
+			w.style = style
+		}
+
+	case TokenOSC:
+		// Hyperlinks are formatted as "8;params;uri".
+		data := string(tok.Data)
+		if !strings.HasPrefix(data, "8;") {
+			return
+		}
+
+		uri := ""
+		if i := strings.IndexByte(data[2:], ';'); i >= 0 {
+			uri = data[2+i+1:]
+		}
+		if !safeURI(uri) {
+			uri = ""
+		}
+
+		if uri != w.link {
+			w.closeSpan()
+			w.closeLink()
+			w.link = uri
+		}
+	}
+}
+
+func (w *HTMLWriter) writeText(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	if w.link != "" && !w.linkOpen {
+		fmt.Fprintf(&w.buf, `<a href="%s">`, escapeHTML(w.link))
+		w.linkOpen = true
+	}
+	if !w.open && w.style != (Style{}) {
+		fmt.Fprintf(&w.buf, "<span %s>", w.attributes())
+		w.open = true
+	}
+
+	w.buf.WriteString(escapeHTML(string(p)))
+}
+
+func (w *HTMLWriter) closeSpan() {
+	if w.open {
+		w.buf.WriteString("</span>")
+		w.open = false
+	}
+}
+
+func (w *HTMLWriter) closeLink() {
+	if w.linkOpen {
+		w.buf.WriteString("</a>")
+		w.linkOpen = false
+	}
+}
+
+// attributes gets the class and style attributes for the current style.
+func (w *HTMLWriter) attributes() string {
+	s := w.style
+	var classes, styles []string
+
+	fg, bg := s.Foreground, s.Background
+	if s.Inverse {
+		fg, bg = bg, fg
+	}
+
+	color := func(c Color, property, class, def string) {
+		switch {
+		case c.Kind == ColorDefault:
+			if def != "" {
+				styles = append(styles, property+":"+def)
+			}
+		case w.classes && c.Kind == ColorIndexed && c.Index < 16:
+			classes = append(classes, w.prefix+class+colorNames[c.Index])
+		default:
+			styles = append(styles, property+":"+c.Hex())
+		}
+	}
+
+	var defFG, defBG string
+	if s.Inverse {
+		defFG, defBG = w.background, w.foreground
+	}
+	color(fg, "color", "", defFG)
+	color(bg, "background-color", "bg-", defBG)
+
+	flag := func(set bool, class, style string) {
+		if !set {
+			return
+		}
+		if w.classes {
+			classes = append(classes, w.prefix+class)
+		} else {
+			styles = append(styles, style)
+		}
+	}
+	flag(s.Bold, "bold", "font-weight:bold")
+	flag(s.Dim, "dim", "opacity:0.5")
+	flag(s.Italic, "italic", "font-style:italic")
+	flag(s.Hidden, "hidden", "visibility:hidden")
+
+	var decorations []string
+	if s.Underline {
+		decorations = append(decorations, "underline")
+	}
+	if s.Strikethrough {
+		decorations = append(decorations, "line-through")
+	}
+	if s.Blink {
+		decorations = append(decorations, "blink")
+	}
+	if w.classes {
+		flag(s.Underline, "underline", "")
+		flag(s.Strikethrough, "strikethrough", "")
+		flag(s.Blink, "blink", "")
+	} else if len(decorations) > 0 {
+		styles = append(styles, "text-decoration:"+strings.Join(decorations, " "))
+	}
+
+	var attrs []string
+	if len(classes) > 0 {
+		attrs = append(attrs, `class="`+strings.Join(classes, " ")+`"`)
+	}
+	if len(styles) > 0 {
+		attrs = append(attrs, `style="`+escapeHTML(strings.Join(styles, ";"))+`"`)
+	}
+
+	return strings.Join(attrs, " ")
+}
+
+var colorNames = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow",
+	"bright-blue", "bright-magenta", "bright-cyan", "bright-white",
+}
+
+// Stylesheet gets CSS rules for classes starting with prefix written by an
+// HTMLWriter using WithClasses. Standard colors use the default xterm palette.
+func Stylesheet(prefix string) string {
+	var sb strings.Builder
+	for i, name := range colorNames {
+		hex := IndexedColor(uint8(i)).Hex()
+		fmt.Fprintf(&sb, ".%s%s { color: %s; }\n", prefix, name, hex)
+		fmt.Fprintf(&sb, ".%sbg-%s { background-color: %s; }\n", prefix, name, hex)
+	}
+
+	sb.WriteString("." + prefix + "bold { font-weight: bold; }\n")
+	sb.WriteString("." + prefix + "dim { opacity: 0.5; }\n")
+	sb.WriteString("." + prefix + "italic { font-style: italic; }\n")
+	sb.WriteString("." + prefix + "underline { text-decoration: underline; }\n")
+	sb.WriteString("." + prefix + "strikethrough { text-decoration: line-through; }\n")
+	sb.WriteString("." + prefix + "underline." + prefix + "strikethrough { text-decoration: underline line-through; }\n")
+	sb.WriteString("." + prefix + "blink { text-decoration: blink; }\n")
+	sb.WriteString("." + prefix + "hidden { visibility: hidden; }\n")
+
+	return sb.String()
+}
+
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&#39;",
+)
+
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// safeURI returns whether uri uses a scheme allowed in links. Only http,
+// https, mailto, and file are allowed, and any control characters reject the
+// uri since browsers may ignore them e.g., "java\tscript:".
+func safeURI(uri string) bool {
+	for i := 0; i < len(uri); i++ {
+		if uri[i] < 0x20 || uri[i] == 0x7f {
+			return false
+		}
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "http", "https", "mailto", "file":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		opts []HTMLOption
+		want string
+	}{
+		{
+			name: "plain",
+			s:    "a < b & c\r\n",
+			want: "a &lt; b &amp; c\n",
+		},
+		{
+			name: "colors",
+			s:    "\x1b[0;1;31merror\x1b[0m: \x1b[38;5;110mdetails\x1b[m",
+			want: `<span style="color:#cd0000;font-weight:bold">error</span>: <span style="color:#87afd7">details</span>`,
+		},
+		{
+			name: "decorations",
+			s:    "\x1b[4;9;48;2;1;2;3mtext",
+			want: `<span style="background-color:#010203;text-decoration:underline line-through">text</span>`,
+		},
+		{
+			name: "inverse",
+			s:    "\x1b[7mtext\x1b[32mgreen",
+			want: `<span style="color:#000000;background-color:#e5e5e5">text</span><span style="color:#000000;background-color:#00cd00">green</span>`,
+		},
+		{
+			name: "decorations blink",
+			s:    "\x1b[4;5mtext",
+			want: `<span style="text-decoration:underline blink">text</span>`,
+		},
+		{
+			name: "inverse classes",
+			s:    "\x1b[7;31;44mtext\x1b[49mdefault",
+			opts: []HTMLOption{WithClasses("ansi-")},
+			want: `<span class="ansi-blue ansi-bg-red">text</span><span class="ansi-bg-red" style="color:#000000">default</span>`,
+		},
+		{
+			name: "classes",
+			s:    "\x1b[1;91;44mbold\x1b[22;38;2;1;2;3mrgb\x1b[0m",
+			opts: []HTMLOption{WithClasses("ansi-")},
+			want: `<span class="ansi-bright-red ansi-bg-blue ansi-bold">bold</span><span class="ansi-bg-blue" style="color:#010203">rgb</span>`,
+		},
+		{
+			name: "link",
+			s:    "see \x1b]8;;https://example.com/?a=1&b=2\x1b\\\x1b[36mdocs\x1b]8;;\x07.",
+			want: `see <a href="https://example.com/?a=1&amp;b=2"><span style="color:#00cdcd">docs</span></a><span style="color:#00cdcd">.</span>`,
+		},
+		{
+			name: "unsafe link",
+			s:    "\x1b]8;;javascript:alert(1)\x1b\\click\x1b]8;;\x1b\\",
+			want: "click",
+		},
+		{
+			name: "unsafe link tab",
+			s:    "\x1b]8;;java\tscript:alert(1)\x1b\\click\x1b]8;;\x1b\\",
+			want: "click",
+		},
+		{
+			name: "unsafe link control",
+			s:    "\x1b]8;;\x01javascript:alert(1)\x1b\\click\x1b]8;;\x1b\\",
+			want: "click",
+		},
+		{
+			name: "unsafe link uppercase",
+			s:    "\x1b]8;;JavaScript:alert(1)\x1b\\click\x1b]8;;\x1b\\",
+			want: "click",
+		},
+		{
+			name: "mailto link",
+			s:    "\x1b]8;;mailto:me@example.com\x1b\\mail\x1b]8;;\x1b\\",
+			want: `<a href="mailto:me@example.com">mail</a>`,
+		},
+		{
+			name: "other sequences",
+			s:    "\x1b[2K\x1b[?25lhello\a\x1b]0;title\x07",
+			want: "hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.s, tt.opts...); got != tt.want {
+				t.Fatalf("HTML() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLWriter_split(t *testing.T) {
+	var sb strings.Builder
+	w := NewHTMLWriter(&sb)
+
+	for _, s := range []string{"\x1b[3", "1mred\x1b", "[0m plain"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := `<span style="color:#cd0000">red</span> plain`
+	if got := sb.String(); got != want {
+		t.Fatalf("Write() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestStylesheet(t *testing.T) {
+	got := Stylesheet("ansi-")
+	for _, rule := range []string{
+		".ansi-red { color: #cd0000; }",
+		".ansi-bg-bright-white { background-color: #ffffff; }",
+		".ansi-bold { font-weight: bold; }",
+	} {
+		if !strings.Contains(got, rule) {
+			t.Fatalf("Stylesheet() = %q, expected to contain %q", got, rule)
+		}
+	}
+}
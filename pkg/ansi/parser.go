@@ -0,0 +1,57 @@
+package ansi
+
+// Parser separates text from escape sequences: CSI, OSC, DCS, SOS, PM, APC,
+// SS3, and other sequences starting with ESC. Other control characters e.g.,
+// "\n" are text. The zero value is ready to use, and escape sequences may span
+// calls to Parse e.g., when parsing each Write. Use a Tokenizer to get the
+// parts of each escape sequence.
+type Parser struct {
+	t       Tokenizer
+	text    []byte
+	invalid []byte
+}
+
+// Parse parses b and calls text with each run of text, and sequence with each
+// complete escape sequence or one interrupted by another. Either function may
+// be nil. Slices passed to either function are only valid until it returns.
+// Sequences cancelled with CAN or SUB are discarded.
+func (p *Parser) Parse(b []byte, text, sequence func([]byte)) {
+	p.t.Tokenize(b, func(tok Token) {
+		if tok.Kind == TokenControl && (tok.Final == can || tok.Final == sub) {
+			// The Tokenizer passes a cancelled sequence as invalid first.
+			p.invalid = p.invalid[:0]
+			return
+		}
+		p.flushInvalid(sequence)
+
+		switch tok.Kind {
+		case TokenText, TokenControl:
+			p.text = append(p.text, tok.Raw...)
+		case TokenInvalid:
+			p.flushText(text)
+			p.invalid = append(p.invalid, tok.Raw...)
+		default:
+			p.flushText(text)
+			if sequence != nil {
+				sequence(tok.Raw)
+			}
+		}
+	})
+
+	p.flushInvalid(sequence)
+	p.flushText(text)
+}
+
+func (p *Parser) flushText(text func([]byte)) {
+	if len(p.text) > 0 && text != nil {
+		text(p.text)
+	}
+	p.text = p.text[:0]
+}
+
+func (p *Parser) flushInvalid(sequence func([]byte)) {
+	if len(p.invalid) > 0 && sequence != nil {
+		sequence(p.invalid)
+	}
+	p.invalid = p.invalid[:0]
+}
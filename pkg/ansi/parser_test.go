@@ -0,0 +1,83 @@
+package ansi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "text",
+			writes: []string{"hello\r\n"},
+			want:   []string{"text:hello\r\n"},
+		},
+		{
+			name:   "csi",
+			writes: []string{"a\x1b[1;31mb\x1b[?25lc"},
+			want:   []string{"text:a", "seq:\x1b[1;31m", "text:b", "seq:\x1b[?25l", "text:c"},
+		},
+		{
+			name:   "osc",
+			writes: []string{"\x1b]0;title\a\x1b]8;;https://example.com\x1b\\link"},
+			want:   []string{"seq:\x1b]0;title\a", "seq:\x1b]8;;https://example.com\x1b\\", "text:link"},
+		},
+		{
+			name:   "dcs",
+			writes: []string{"\x1bP+q544e\x1b\\a"},
+			want:   []string{"seq:\x1bP+q544e\x1b\\", "text:a"},
+		},
+		{
+			name:   "escape",
+			writes: []string{"\x1b7a\x1b(Bb\x1bOPc"},
+			want:   []string{"seq:\x1b7", "text:a", "seq:\x1b(B", "text:b", "seq:\x1bOP", "text:c"},
+		},
+		{
+			name:   "split",
+			writes: []string{"a\x1b", "[3", "1mb\x1b]0;ti", "tle\x1b", "\\c"},
+			want:   []string{"text:a", "seq:\x1b[31m", "text:b", "seq:\x1b]0;title\x1b\\", "text:c"},
+		},
+		{
+			name:   "split utf8",
+			writes: []string{"caf\xc3", "\xa9\r\n"},
+			want:   []string{"text:caf", "text:é\r\n"},
+		},
+		{
+			name:   "restart",
+			writes: []string{"\x1b[1\x1b[2ma"},
+			want:   []string{"seq:\x1b[1", "seq:\x1b[2m", "text:a"},
+		},
+		{
+			name:   "string restart",
+			writes: []string{"\x1b]0;title\x1b[2ma"},
+			want:   []string{"seq:\x1b]0;title", "seq:\x1b[2m", "text:a"},
+		},
+		{
+			name:   "cancel",
+			writes: []string{"\x1b[1\x18a"},
+			want:   []string{"text:a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var p Parser
+			for _, w := range tt.writes {
+				p.Parse([]byte(w), func(b []byte) {
+					got = append(got, "text:"+string(b))
+				}, func(b []byte) {
+					got = append(got, "seq:"+string(b))
+				})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
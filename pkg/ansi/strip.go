@@ -0,0 +1,92 @@
+package ansi
+
+import (
+	"io"
+	"strings"
+)
+
+// Strip removes escape sequences from s.
+func Strip(s string) string {
+	var sb strings.Builder
+	write := func(tok Token) {
+		if isText(tok) {
+			sb.Write(tok.Raw)
+		}
+	}
+
+	var t Tokenizer
+	t.Tokenize([]byte(s), write)
+	t.Flush(write)
+
+	return sb.String()
+}
+
+// isText returns whether tok is text or a control character other than CAN
+// or SUB, which cancel escape sequences.
+func isText(tok Token) bool {
+	switch tok.Kind {
+	case TokenText:
+		return true
+	case TokenControl:
+		return tok.Final != can && tok.Final != sub
+	}
+
+	return false
+}
+
+// StripWriter removes escape sequences and writes only text to an underlying
+// Writer. Escape sequences and UTF-8 sequences may span writes.
+type StripWriter struct {
+	w   io.Writer
+	t   Tokenizer
+	buf []byte
+}
+
+// NewStripWriter gets a StripWriter that writes text to w. Call Close to write
+// any incomplete UTF-8 sequence at the end of the last write.
+func NewStripWriter(w io.Writer) *StripWriter {
+	return &StripWriter{w: w}
+}
+
+// Write writes text in p to the underlying Writer. An incomplete UTF-8
+// sequence at the end of p is held until the next write or Flush.
+func (sw *StripWriter) Write(p []byte) (n int, err error) {
+	sw.buf = sw.buf[:0]
+	sw.t.Tokenize(p, sw.append)
+
+	if err := sw.write(); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any incomplete UTF-8 sequence held from the last write and
+// discards any incomplete escape sequence.
+func (sw *StripWriter) Flush() error {
+	sw.buf = sw.buf[:0]
+	sw.t.Flush(sw.append)
+
+	return sw.write()
+}
+
+// Close calls Flush. It does not close the underlying Writer.
+func (sw *StripWriter) Close() error {
+	return sw.Flush()
+}
+
+func (sw *StripWriter) append(tok Token) {
+	if isText(tok) {
+		sw.buf = append(sw.buf, tok.Raw...)
+	}
+}
+
+func (sw *StripWriter) write() error {
+	if len(sw.buf) > 0 {
+		if _, err := sw.w.Write(sw.buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
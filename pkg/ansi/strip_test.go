@@ -0,0 +1,75 @@
+package ansi
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "hello", want: "hello"},
+		{name: "sgr", s: "\x1b[0;1;31mhello\x1b[0m", want: "hello"},
+		{name: "private", s: "\x1b[?25lhello\x1b[?25h", want: "hello"},
+		{name: "osc bel", s: "\x1b]0;title\ahello", want: "hello"},
+		{name: "osc st", s: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\a", want: "link"},
+		{name: "dcs", s: "\x1bP+q544e\x1b\\hello", want: "hello"},
+		{name: "escape", s: "\x1b7hello\x1b8", want: "hello"},
+		{name: "controls", s: "a\tb\r\n", want: "a\tb\r\n"},
+		{name: "unterminated", s: "hello\x1b[1", want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strip(tt.s); got != tt.want {
+				t.Fatalf("Strip() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewStripWriter(buf)
+
+	for _, s := range []string{"\x1b[3", "1mhello\x1b", "[0m \x1b]8;;https://", "example.com\x1b\\world\x1b]8;;\x1b\\\n"} {
+		n, err := w.Write([]byte(s))
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if n != len(s) {
+			t.Fatalf("Write() = %d, expected %d", n, len(s))
+		}
+	}
+
+	if got, want := buf.String(), "hello world\n"; got != want {
+		t.Fatalf("Write() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestStripWriter_Close(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewStripWriter(buf)
+
+	// nolint:errcheck
+	w.Write([]byte("caf\xc3"))
+	if got, want := buf.String(), "caf"; got != want {
+		t.Fatalf("Write() wrote %q, expected %q", got, want)
+	}
+
+	// nolint:errcheck
+	w.Write([]byte("\xa9 \xe2\x82"))
+	if got, want := buf.String(), "café "; got != want {
+		t.Fatalf("Write() wrote %q, expected %q", got, want)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, want := buf.String(), "café \xe2\x82"; got != want {
+		t.Fatalf("Close() wrote %q, expected %q", got, want)
+	}
+}
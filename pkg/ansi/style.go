@@ -0,0 +1,247 @@
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColorKind is the kind of a Color.
+type ColorKind uint8
+
+const (
+	// ColorDefault is the terminal's default foreground or background color.
+	ColorDefault ColorKind = iota
+
+	// ColorIndexed is one of 256 colors in the terminal's palette. The first
+	// 16 are the standard and bright colors.
+	ColorIndexed
+
+	// ColorRGB is a 24-bit color.
+	ColorRGB
+)
+
+// Color is a foreground or background color set using SGR.
+type Color struct {
+	Kind  ColorKind
+	Index uint8
+	R     uint8
+	G     uint8
+	B     uint8
+}
+
+// IndexedColor gets a Color from the terminal's palette.
+func IndexedColor(index uint8) Color {
+	return Color{Kind: ColorIndexed, Index: index}
+}
+
+// RGBColor gets a 24-bit Color.
+func RGBColor(r, g, b uint8) Color {
+	return Color{Kind: ColorRGB, R: r, G: g, B: b}
+}
+
+// RGB gets the red, green, and blue components of the color using the default
+// xterm palette for indexed colors. It returns false for the default color.
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	switch c.Kind {
+	case ColorRGB:
+		return c.R, c.G, c.B, true
+
+	case ColorIndexed:
+		switch i := int(c.Index); {
+		case i < 16:
+			p := palette[i]
+			return p[0], p[1], p[2], true
+		case i < 232:
+			// 6x6x6 color cube.
+			i -= 16
+			return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6], true
+		default:
+			// Grayscale ramp.
+			v := uint8(8 + (i-232)*10)
+			return v, v, v, true
+		}
+	}
+
+	return 0, 0, 0, false
+}
+
+// Hex gets the color formatted as "#rrggbb", or "" for the default color.
+func (c Color) Hex() string {
+	r, g, b, ok := c.RGB()
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+var (
+	palette = [16][3]uint8{
+		{0x00, 0x00, 0x00},
+		{0xcd, 0x00, 0x00},
+		{0x00, 0xcd, 0x00},
+		{0xcd, 0xcd, 0x00},
+		{0x00, 0x00, 0xee},
+		{0xcd, 0x00, 0xcd},
+		{0x00, 0xcd, 0xcd},
+		{0xe5, 0xe5, 0xe5},
+		{0x7f, 0x7f, 0x7f},
+		{0xff, 0x00, 0x00},
+		{0x00, 0xff, 0x00},
+		{0xff, 0xff, 0x00},
+		{0x5c, 0x5c, 0xff},
+		{0xff, 0x00, 0xff},
+		{0x00, 0xff, 0xff},
+		{0xff, 0xff, 0xff},
+	}
+
+	cubeLevels = [6]uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+)
+
+// Style is the graphic rendition of text set using SGR.
+type Style struct {
+	Foreground    Color
+	Background    Color
+	Bold          bool
+	Dim           bool
+	Italic        bool
+	Underline     bool
+	Blink         bool
+	Inverse       bool
+	Hidden        bool
+	Strikethrough bool
+}
+
+// SGR applies select graphic rendition parameters e.g., "1;31" from a CSI
+// sequence ending with "m" and returns the new style. Unsupported parameters
+// are ignored.
+func (s Style) SGR(params string) Style {
+	if params == "" {
+		return Style{}
+	}
+
+	parts := strings.Split(params, ";")
+	for i := 0; i < len(parts); i++ {
+		// Sub-parameters are separated by colons e.g., "4:3" or "38:2::255:0:0".
+		sub := strings.Split(parts[i], ":")
+		n, _ := strconv.Atoi(sub[0])
+
+		switch {
+		case n == 0:
+			s = Style{}
+		case n == 1:
+			s.Bold = true
+		case n == 2:
+			s.Dim = true
+		case n == 3:
+			s.Italic = true
+		case n == 4:
+			s.Underline = len(sub) < 2 || sub[1] != "0"
+		case n == 5 || n == 6:
+			s.Blink = true
+		case n == 7:
+			s.Inverse = true
+		case n == 8:
+			s.Hidden = true
+		case n == 9:
+			s.Strikethrough = true
+		case n == 21:
+			s.Underline = true
+		case n == 22:
+			s.Bold, s.Dim = false, false
+		case n == 23:
+			s.Italic = false
+		case n == 24:
+			s.Underline = false
+		case n == 25:
+			s.Blink = false
+		case n == 27:
+			s.Inverse = false
+		case n == 28:
+			s.Hidden = false
+		case n == 29:
+			s.Strikethrough = false
+		case n >= 30 && n <= 37:
+			s.Foreground = IndexedColor(uint8(n - 30))
+		case n == 38:
+			var c Color
+			c, i = extendedColor(sub, parts, i)
+			s.Foreground = c
+		case n == 39:
+			s.Foreground = Color{}
+		case n >= 40 && n <= 47:
+			s.Background = IndexedColor(uint8(n - 40))
+		case n == 48:
+			var c Color
+			c, i = extendedColor(sub, parts, i)
+			s.Background = c
+		case n == 49:
+			s.Background = Color{}
+		case n >= 90 && n <= 97:
+			s.Foreground = IndexedColor(uint8(n - 90 + 8))
+		case n >= 100 && n <= 107:
+			s.Background = IndexedColor(uint8(n - 100 + 8))
+		}
+	}
+
+	return s
+}
+
+// extendedColor parses an indexed or RGB color from either sub-parameters
+// e.g., "38:5:196" or following parameters e.g., "38;5;196", and returns the
+// index of the last parameter parsed.
+func extendedColor(sub, parts []string, i int) (Color, int) {
+	args := sub[1:]
+	colon := len(args) > 0
+	if !colon {
+		args = parts[i+1:]
+	}
+
+	arg := func(j int) (uint8, bool) {
+		if j >= len(args) {
+			return 0, false
+		}
+		v, err := strconv.Atoi(args[j])
+		if err != nil && args[j] != "" {
+			return 0, false
+		}
+		return uint8(v), true
+	}
+
+	kind, ok := arg(0)
+	if !ok {
+		return Color{}, i
+	}
+
+	switch kind {
+	case 5:
+		index, ok := arg(1)
+		if !colon {
+			i += 2
+		}
+		if !ok {
+			return Color{}, i
+		}
+		return IndexedColor(index), i
+
+	case 2:
+		// The colon form may include a color space ID e.g., "38:2::255:0:0".
+		first := 1
+		if colon && len(args) >= 5 {
+			first = 2
+		}
+		r, rok := arg(first)
+		g, gok := arg(first + 1)
+		b, bok := arg(first + 2)
+		if !colon {
+			i += 4
+		}
+		if !rok || !gok || !bok {
+			return Color{}, i
+		}
+		return RGBColor(r, g, b), i
+	}
+
+	return Color{}, i
+}
@@ -0,0 +1,106 @@
+package ansi
+
+import (
+	"testing"
+)
+
+func TestStyle_SGR(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  Style
+		params string
+		want   Style
+	}{
+		{
+			name:   "reset",
+			style:  Style{Bold: true, Foreground: IndexedColor(1)},
+			params: "",
+			want:   Style{},
+		},
+		{
+			name:   "attributes",
+			params: "1;3;4;7;9",
+			want:   Style{Bold: true, Italic: true, Underline: true, Inverse: true, Strikethrough: true},
+		},
+		{
+			name:   "attributes off",
+			style:  Style{Bold: true, Dim: true, Italic: true, Underline: true, Inverse: true},
+			params: "22;23;24;27",
+			want:   Style{},
+		},
+		{
+			name:   "underline off",
+			style:  Style{Underline: true},
+			params: "4:0",
+			want:   Style{},
+		},
+		{
+			name:   "colors",
+			params: "0;31;42",
+			want:   Style{Foreground: IndexedColor(1), Background: IndexedColor(2)},
+		},
+		{
+			name:   "bright colors",
+			params: "91;102",
+			want:   Style{Foreground: IndexedColor(9), Background: IndexedColor(10)},
+		},
+		{
+			name:   "default colors",
+			style:  Style{Foreground: IndexedColor(1), Background: IndexedColor(2)},
+			params: "39;49",
+			want:   Style{},
+		},
+		{
+			name:   "256 colors",
+			params: "38;5;196;1",
+			want:   Style{Foreground: IndexedColor(196), Bold: true},
+		},
+		{
+			name:   "rgb colors",
+			params: "38;2;1;2;3;48;2;4;5;6",
+			want:   Style{Foreground: RGBColor(1, 2, 3), Background: RGBColor(4, 5, 6)},
+		},
+		{
+			name:   "rgb sub-parameters",
+			params: "38:2::1:2:3;48:5:42",
+			want:   Style{Foreground: RGBColor(1, 2, 3), Background: IndexedColor(42)},
+		},
+		{
+			name:   "incomplete",
+			params: "1;38;5",
+			want:   Style{Bold: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.SGR(tt.params); got != tt.want {
+				t.Fatalf("SGR() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColor_Hex(t *testing.T) {
+	tests := []struct {
+		name  string
+		color Color
+		want  string
+	}{
+		{name: "default", color: Color{}, want: ""},
+		{name: "red", color: IndexedColor(1), want: "#cd0000"},
+		{name: "bright blue", color: IndexedColor(12), want: "#5c5cff"},
+		{name: "cube", color: IndexedColor(196), want: "#ff0000"},
+		{name: "cube mixed", color: IndexedColor(110), want: "#87afd7"},
+		{name: "gray", color: IndexedColor(244), want: "#808080"},
+		{name: "rgb", color: RGBColor(0x12, 0x34, 0x56), want: "#123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.Hex(); got != tt.want {
+				t.Fatalf("Hex() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,585 @@
+package ansi
+
+import (
+	"unicode/utf8"
+)
+
+const (
+	esc = 0x1b
+	bel = 0x07
+	can = 0x18
+	sub = 0x1a
+	del = 0x7f
+
+	// maxSequenceLength is the most bytes of an escape sequence buffered
+	// before the rest of it is discarded.
+	maxSequenceLength = 64 * 1024
+)
+
+// TokenKind is the kind of a Token.
+type TokenKind uint8
+
+const (
+	// TokenText is a run of printable text. Text contains only complete UTF-8
+	// sequences unless the input is not valid UTF-8.
+	TokenText TokenKind = iota
+
+	// TokenControl is a single C0 control character e.g., "\n" or DEL,
+	// including those that occur within an escape sequence.
+	TokenControl
+
+	// TokenEscape is ESC followed by any intermediate bytes and a final byte
+	// e.g., "\x1b7" or "\x1b(B".
+	TokenEscape
+
+	// TokenCSI is a control sequence e.g., "\x1b[1;31m".
+	TokenCSI
+
+	// TokenOSC is an operating system command terminated by BEL or ST e.g.,
+	// "\x1b]0;title\a".
+	TokenOSC
+
+	// TokenDCS is a device control string terminated by ST.
+	TokenDCS
+
+	// TokenSS3 is a single shift 3 followed by one character e.g., "\x1bOP".
+	TokenSS3
+
+	// TokenString is a start of string (SOS), privacy message (PM), or
+	// application program command (APC) terminated by ST.
+	TokenString
+
+	// TokenInvalid is a malformed or interrupted escape sequence a terminal
+	// would ignore, or the start of one longer than a Tokenizer buffers.
+	TokenInvalid
+)
+
+var tokenKinds = [...]string{
+	TokenText:    "Text",
+	TokenControl: "Control",
+	TokenEscape:  "Escape",
+	TokenCSI:     "CSI",
+	TokenOSC:     "OSC",
+	TokenDCS:     "DCS",
+	TokenSS3:     "SS3",
+	TokenString:  "String",
+	TokenInvalid: "Invalid",
+}
+
+func (k TokenKind) String() string {
+	if int(k) < len(tokenKinds) {
+		return tokenKinds[k]
+	}
+	return "Unknown"
+}
+
+// Token is text, a control character, or an escape sequence. Slices are only
+// valid until the function passed to Tokenize returns.
+type Token struct {
+	Kind TokenKind
+
+	// Raw is the bytes of the token as written, excluding control characters
+	// executed within an escape sequence.
+	Raw []byte
+
+	// Private is the private marker of a CSI or DCS e.g., '?', or 0.
+	Private byte
+
+	// Params are the parameter bytes of a CSI or DCS without the private
+	// marker e.g., "1;31" or "38:2::255:0:0".
+	Params []byte
+
+	// Intermediates are the intermediate bytes of an escape sequence, CSI, or
+	// DCS e.g., "(" in "\x1b(B".
+	Intermediates []byte
+
+	// Final is the final byte of an escape sequence, CSI, or DCS; the
+	// character following SS3; or the control character.
+	Final byte
+
+	// Data is the content of an OSC, DCS, or other string without its
+	// terminator.
+	Data []byte
+}
+
+// NumParams gets the number of parameters separated by ";".
+func (t Token) NumParams() int {
+	if len(t.Params) == 0 {
+		return 0
+	}
+
+	n := 1
+	for _, c := range t.Params {
+		if c == ';' {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Param gets parameter i separated by ";", ignoring any sub-parameters
+// separated by ":", or def if missing or empty.
+func (t Token) Param(i, def int) int {
+	index, value := 0, -1
+	sub := false
+	for _, c := range t.Params {
+		if c == ';' {
+			if index == i {
+				break
+			}
+			index++
+			sub = false
+			continue
+		}
+
+		switch {
+		case index != i || sub:
+		case c == ':':
+			// Ignore sub-parameters.
+			sub = true
+		case c >= '0' && c <= '9':
+			if value < 0 {
+				value = 0
+			}
+			value = value*10 + int(c-'0')
+		}
+	}
+
+	if index != i || value < 0 {
+		return def
+	}
+	return value
+}
+
+type tokenizerState uint8
+
+const (
+	stateGround tokenizerState = iota
+	stateEscape
+	stateEscapeIntermediate
+	stateParams
+	stateIntermediate
+	stateIgnore
+	stateSS3
+	stateString
+	stateStringEscape
+	stateDiscard
+	stateDiscardString
+	stateDiscardStringEscape
+)
+
+// Tokenizer splits a stream of bytes into text, control characters, and
+// escape sequences using the state machine of a DEC VT500-series terminal.
+// Escape sequences and UTF-8 sequences may span calls to Tokenize. 8-bit C1
+// control characters are not recognized so they do not conflict with UTF-8.
+// An escape sequence longer than 64 KiB e.g., an unterminated OSC, is passed
+// as TokenInvalid once and the rest of it is discarded. The zero value is
+// ready to use.
+type Tokenizer struct {
+	state tokenizerState
+	kind  TokenKind
+
+	// unlimited buffers escape sequences of any length.
+	unlimited bool
+
+	// Bytes of the current escape sequence and where its parts start.
+	raw         []byte
+	private     byte
+	paramStart  int
+	interStart  int
+	dataStart   int
+	escapeStart int
+
+	// Incomplete UTF-8 sequence at the end of the previous text.
+	utf8  [utf8.UTFMax]byte
+	utf8n int
+
+	control [1]byte
+}
+
+// Tokenize splits p into tokens and calls fn with each complete token. Text
+// is passed as it is read, so consecutive TokenText tokens may be passed.
+func (t *Tokenizer) Tokenize(p []byte, fn func(Token)) {
+	start := 0
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if t.state == stateGround {
+			if c >= 0x20 && c != del {
+				continue
+			}
+
+			t.text(p[start:i], false, fn)
+			start = i + 1
+
+			if c == esc {
+				t.begin()
+			} else {
+				fn(Token{Kind: TokenControl, Raw: p[i : i+1], Final: c})
+			}
+			continue
+		}
+
+		t.step(c, fn)
+		start = i + 1
+	}
+
+	if t.state == stateGround {
+		t.text(p[start:], true, fn)
+	}
+}
+
+// Flush passes any incomplete UTF-8 sequence as TokenText and escape sequence
+// as TokenInvalid to fn e.g., at the end of the stream.
+func (t *Tokenizer) Flush(fn func(Token)) {
+	if t.state == stateGround {
+		t.text(nil, false, fn)
+		return
+	}
+
+	t.dispatch(TokenInvalid, fn)
+}
+
+// Reset discards any incomplete escape or UTF-8 sequence.
+func (t *Tokenizer) Reset() {
+	t.state = stateGround
+	t.raw = t.raw[:0]
+	t.utf8n = 0
+}
+
+// text passes text to fn, completing an incomplete UTF-8 sequence from
+// previous text and, if more may follow, holding back one at the end.
+func (t *Tokenizer) text(p []byte, more bool, fn func(Token)) {
+	if t.utf8n > 0 {
+		for len(p) > 0 && t.utf8n < len(t.utf8) && !utf8.FullRune(t.utf8[:t.utf8n]) && !utf8.RuneStart(p[0]) {
+			t.utf8[t.utf8n] = p[0]
+			t.utf8n++
+			p = p[1:]
+		}
+
+		if len(p) == 0 && more && !utf8.FullRune(t.utf8[:t.utf8n]) {
+			return
+		}
+
+		fn(Token{Kind: TokenText, Raw: t.utf8[:t.utf8n]})
+		t.utf8n = 0
+	}
+
+	if more {
+		// Hold back an incomplete UTF-8 sequence at the end.
+		for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(p[i]) {
+				if !utf8.FullRune(p[i:]) {
+					t.utf8n = copy(t.utf8[:], p[i:])
+					p = p[:i]
+				}
+				break
+			}
+		}
+	}
+
+	if len(p) > 0 {
+		fn(Token{Kind: TokenText, Raw: p})
+	}
+}
+
+// begin starts an escape sequence.
+func (t *Tokenizer) begin() {
+	t.state = stateEscape
+	t.kind = TokenEscape
+	t.raw = append(t.raw[:0], esc)
+	t.private = 0
+	t.paramStart, t.interStart, t.dataStart = 1, 1, 1
+}
+
+// step processes byte c within an escape sequence.
+func (t *Tokenizer) step(c byte, fn func(Token)) {
+	// CAN and SUB cancel any sequence, and ESC starts a new one outside of
+	// strings.
+	switch {
+	case c == can || c == sub:
+		t.dispatch(TokenInvalid, fn)
+		t.control[0] = c
+		fn(Token{Kind: TokenControl, Raw: t.control[:], Final: c})
+		return
+
+	case c == esc && !t.inString():
+		t.dispatch(TokenInvalid, fn)
+		t.begin()
+		return
+
+	case c < 0x20 && !t.inString():
+		// Execute control characters within sequences.
+		t.control[0] = c
+		fn(Token{Kind: TokenControl, Raw: t.control[:], Final: c})
+		return
+	}
+
+	switch t.state {
+	case stateDiscard:
+		if c >= 0x40 && c <= 0x7e {
+			t.state = stateGround
+			if t.kind == TokenDCS {
+				// Discard the string following the final byte.
+				t.state = stateDiscardString
+			}
+		}
+		return
+
+	case stateDiscardString:
+		switch {
+		case c == bel && t.kind == TokenOSC:
+			t.state = stateGround
+		case c == esc:
+			t.state = stateDiscardStringEscape
+		}
+		return
+
+	case stateDiscardStringEscape:
+		if c == '\\' {
+			t.state = stateGround
+			return
+		}
+
+		// Any other escape sequence terminates the string.
+		t.begin()
+		t.step(c, fn)
+		return
+	}
+
+	if len(t.raw) >= maxSequenceLength && !t.unlimited {
+		t.discard(fn)
+		t.step(c, fn)
+		return
+	}
+
+	t.raw = append(t.raw, c)
+
+	switch t.state {
+	case stateEscape:
+		switch {
+		case c == '[':
+			t.kind = TokenCSI
+			t.enterParams()
+		case c == 'P':
+			t.kind = TokenDCS
+			t.enterParams()
+		case c == ']':
+			t.kind = TokenOSC
+			t.enterString()
+		case c == 'X' || c == '^' || c == '_':
+			t.kind = TokenString
+			t.enterString()
+		case c == 'O':
+			t.state = stateSS3
+		case c >= 0x20 && c <= 0x2f:
+			t.interStart = len(t.raw) - 1
+			t.state = stateEscapeIntermediate
+		case c == del:
+		default:
+			t.interStart = len(t.raw) - 1
+			t.dispatch(TokenEscape, fn)
+		}
+
+	case stateEscapeIntermediate:
+		switch {
+		case c >= 0x20 && c <= 0x2f, c == del:
+		default:
+			t.dispatch(TokenEscape, fn)
+		}
+
+	case stateSS3:
+		t.dispatch(TokenSS3, fn)
+
+	case stateParams:
+		switch {
+		case c >= '0' && c <= ';', c == del:
+		case c >= '<' && c <= '?':
+			if len(t.raw)-1 == t.paramStart && t.private == 0 {
+				t.private = c
+				t.paramStart++
+			} else {
+				t.state = stateIgnore
+			}
+		case c >= 0x20 && c <= 0x2f:
+			t.interStart = len(t.raw) - 1
+			t.state = stateIntermediate
+		default:
+			t.interStart = len(t.raw) - 1
+			t.final(fn)
+		}
+
+	case stateIntermediate:
+		switch {
+		case c >= 0x20 && c <= 0x2f, c == del:
+		case c >= 0x30 && c <= 0x3f:
+			t.state = stateIgnore
+		default:
+			t.final(fn)
+		}
+
+	case stateIgnore:
+		if c >= 0x40 && c <= 0x7e {
+			if t.kind == TokenDCS {
+				// Ignore the rest of the string.
+				t.kind = TokenInvalid
+				t.enterString()
+				return
+			}
+			t.dispatch(TokenInvalid, fn)
+		}
+
+	case stateString:
+		switch c {
+		case bel:
+			if t.kind == TokenOSC {
+				t.dispatch(t.kind, fn)
+			}
+		case esc:
+			t.escapeStart = len(t.raw) - 1
+			t.state = stateStringEscape
+		}
+
+	case stateStringEscape:
+		if c == '\\' {
+			t.dispatch(t.kind, fn)
+			return
+		}
+
+		// Any other escape sequence terminates the string.
+		t.raw = t.raw[:t.escapeStart]
+		t.state = stateString
+		t.dispatch(t.kind, fn)
+		t.begin()
+		t.step(c, fn)
+	}
+}
+
+// inString returns whether the tokenizer is within an OSC, DCS, or other
+// string, where ESC may start its terminator.
+func (t *Tokenizer) inString() bool {
+	switch t.state {
+	case stateString, stateStringEscape, stateDiscardString, stateDiscardStringEscape:
+		return true
+	}
+	return false
+}
+
+// discard passes the current escape sequence as TokenInvalid to fn and
+// discards the rest of it.
+func (t *Tokenizer) discard(fn func(Token)) {
+	state := stateDiscard
+	switch t.state {
+	case stateString:
+		state = stateDiscardString
+	case stateStringEscape:
+		state = stateDiscardStringEscape
+	}
+
+	t.dispatch(TokenInvalid, fn)
+	t.state = state
+}
+
+func (t *Tokenizer) enterParams() {
+	t.state = stateParams
+	t.paramStart = len(t.raw)
+	t.interStart = len(t.raw)
+}
+
+func (t *Tokenizer) enterString() {
+	t.state = stateString
+	t.dataStart = len(t.raw)
+}
+
+// final handles the final byte of a CSI or DCS.
+func (t *Tokenizer) final(fn func(Token)) {
+	if t.kind == TokenDCS {
+		// The data follows the final byte.
+		t.enterString()
+		return
+	}
+
+	t.dispatch(TokenCSI, fn)
+}
+
+// dispatch passes the current escape sequence, if any, to fn and returns to
+// the ground state.
+func (t *Tokenizer) dispatch(kind TokenKind, fn func(Token)) {
+	if t.state == stateGround || len(t.raw) == 0 {
+		t.state = stateGround
+		return
+	}
+
+	state := t.state
+	t.state = stateGround
+
+	tok := Token{
+		Kind: kind,
+		Raw:  t.raw,
+	}
+
+	switch kind {
+	case TokenEscape:
+		tok.Intermediates = t.raw[t.interStart : len(t.raw)-1]
+		tok.Final = t.raw[len(t.raw)-1]
+
+	case TokenCSI:
+		tok.Private = t.private
+		tok.Params = t.raw[t.paramStart:t.interStart]
+		tok.Intermediates = t.raw[t.interStart : len(t.raw)-1]
+		tok.Final = t.raw[len(t.raw)-1]
+
+	case TokenSS3:
+		tok.Final = t.raw[len(t.raw)-1]
+
+	case TokenOSC, TokenDCS, TokenString:
+		end := len(t.raw)
+		switch {
+		case state == stateStringEscape && t.raw[end-1] == '\\':
+			end -= 2
+		case kind == TokenOSC && t.raw[end-1] == bel:
+			end--
+		}
+		tok.Data = t.raw[t.dataStart:end]
+
+		if kind == TokenDCS {
+			tok.Private = t.private
+			tok.Params = t.raw[t.paramStart:t.interStart]
+			tok.Intermediates = t.raw[t.interStart : t.dataStart-1]
+			tok.Final = t.raw[t.dataStart-1]
+		}
+	}
+
+	fn(tok)
+	t.raw = t.raw[:0]
+}
+
+// SequenceLength gets the length of the escape sequence at the start of s, or
+// 0 if s does not start with ESC. If s ends before the sequence does, the
+// length of s is returned.
+func SequenceLength(s string) int {
+	if len(s) == 0 || s[0] != esc {
+		return 0
+	}
+
+	t := Tokenizer{unlimited: true}
+	t.begin()
+
+	n, controls := 0, 0
+	for i := 1; i < len(s) && n == 0; i++ {
+		t.step(s[i], func(tok Token) {
+			switch {
+			case n > 0:
+			case tok.Kind == TokenControl:
+				controls++
+			default:
+				n = len(tok.Raw) + controls
+			}
+		})
+	}
+
+	if n == 0 {
+		return len(s)
+	}
+	return n
+}
@@ -0,0 +1,306 @@
+package ansi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tokens tokenizes each write and formats the tokens, joining adjacent text.
+func tokens(writes ...string) []string {
+	var got []string
+	var text string
+	fn := func(tok Token) {
+		if tok.Kind == TokenText {
+			text += string(tok.Raw)
+			return
+		}
+		if text != "" {
+			got = append(got, "Text:"+text)
+			text = ""
+		}
+		got = append(got, fmt.Sprintf("%s:%s", tok.Kind, tok.Raw))
+	}
+
+	var t Tokenizer
+	for _, w := range writes {
+		t.Tokenize([]byte(w), fn)
+	}
+	t.Flush(fn)
+
+	if text != "" {
+		got = append(got, "Text:"+text)
+	}
+	return got
+}
+
+func TestTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "text",
+			writes: []string{"héllo"},
+			want:   []string{"Text:héllo"},
+		},
+		{
+			name:   "controls",
+			writes: []string{"a\r\nb\x7f"},
+			want:   []string{"Text:a", "Control:\r", "Control:\n", "Text:b", "Control:\x7f"},
+		},
+		{
+			name:   "csi",
+			writes: []string{"a\x1b[1;31mb\x1b[?25lc"},
+			want:   []string{"Text:a", "CSI:\x1b[1;31m", "Text:b", "CSI:\x1b[?25l", "Text:c"},
+		},
+		{
+			name:   "osc",
+			writes: []string{"\x1b]0;title\a\x1b]8;;https://example.com\x1b\\link"},
+			want:   []string{"OSC:\x1b]0;title\a", "OSC:\x1b]8;;https://example.com\x1b\\", "Text:link"},
+		},
+		{
+			name:   "dcs",
+			writes: []string{"\x1bP+q544e\x1b\\a"},
+			want:   []string{"DCS:\x1bP+q544e\x1b\\", "Text:a"},
+		},
+		{
+			name:   "strings",
+			writes: []string{"\x1b_apc\x1b\\\x1b^pm\x1b\\"},
+			want:   []string{"String:\x1b_apc\x1b\\", "String:\x1b^pm\x1b\\"},
+		},
+		{
+			name:   "escape",
+			writes: []string{"\x1b7a\x1b(Bb\x1bOPc"},
+			want:   []string{"Escape:\x1b7", "Text:a", "Escape:\x1b(B", "Text:b", "SS3:\x1bOP", "Text:c"},
+		},
+		{
+			name:   "split",
+			writes: []string{"a\x1b", "[3", "1mb\x1b]0;ti", "tle\x1b", "\\c\xe2\x9c", "\x93"},
+			want:   []string{"Text:a", "CSI:\x1b[31m", "Text:b", "OSC:\x1b]0;title\x1b\\", "Text:c✓"},
+		},
+		{
+			name:   "control in sequence",
+			writes: []string{"\x1b[1\n;2m"},
+			want:   []string{"Control:\n", "CSI:\x1b[1;2m"},
+		},
+		{
+			name:   "restart",
+			writes: []string{"\x1b[1\x1b[2ma"},
+			want:   []string{"Invalid:\x1b[1", "CSI:\x1b[2m", "Text:a"},
+		},
+		{
+			name:   "string restart",
+			writes: []string{"\x1b]0;title\x1b[2ma"},
+			want:   []string{"OSC:\x1b]0;title", "CSI:\x1b[2m", "Text:a"},
+		},
+		{
+			name:   "cancel",
+			writes: []string{"\x1b[1\x18a"},
+			want:   []string{"Invalid:\x1b[1", "Control:\x18", "Text:a"},
+		},
+		{
+			name:   "ignore",
+			writes: []string{"\x1b[1?2ma"},
+			want:   []string{"Invalid:\x1b[1?2m", "Text:a"},
+		},
+		{
+			name:   "incomplete",
+			writes: []string{"a\x1b[1"},
+			want:   []string{"Text:a", "Invalid:\x1b[1"},
+		},
+		{
+			name:   "invalid utf-8",
+			writes: []string{"a\xe2\x9c", "\x1b[mb"},
+			want:   []string{"Text:a\xe2\x9c", "CSI:\x1b[m", "Text:b"},
+		},
+		{
+			name:   "long osc",
+			writes: []string{"\x1b]0;" + strings.Repeat("a", maxSequenceLength) + "\ab"},
+			want:   []string{"Invalid:\x1b]0;" + strings.Repeat("a", maxSequenceLength-4), "Text:b"},
+		},
+		{
+			name:   "long osc terminator",
+			writes: []string{"\x1b]0;" + strings.Repeat("a", maxSequenceLength-5) + "\x1b\\b"},
+			want:   []string{"Invalid:\x1b]0;" + strings.Repeat("a", maxSequenceLength-5) + "\x1b", "Text:b"},
+		},
+		{
+			name:   "long osc restart",
+			writes: []string{"\x1b]0;" + strings.Repeat("a", maxSequenceLength) + "\x1b[2mb"},
+			want:   []string{"Invalid:\x1b]0;" + strings.Repeat("a", maxSequenceLength-4), "CSI:\x1b[2m", "Text:b"},
+		},
+		{
+			name:   "long csi",
+			writes: []string{"\x1b[" + strings.Repeat("1", maxSequenceLength) + "mb"},
+			want:   []string{"Invalid:\x1b[" + strings.Repeat("1", maxSequenceLength-2), "Text:b"},
+		},
+		{
+			name:   "long dcs",
+			writes: []string{"\x1bP" + strings.Repeat("1", maxSequenceLength) + "qdata\x1b\\b"},
+			want:   []string{"Invalid:\x1bP" + strings.Repeat("1", maxSequenceLength-2), "Text:b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokens(tt.writes...); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize() = %q, expected %q", got, tt.want)
+			}
+
+			// Tokens should be the same when written one byte at a time.
+			var bytes []string
+			for _, w := range tt.writes {
+				for i := 0; i < len(w); i++ {
+					bytes = append(bytes, w[i:i+1])
+				}
+			}
+			if got := tokens(bytes...); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize() bytes = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizer_fields(t *testing.T) {
+	type fields struct {
+		Kind          TokenKind
+		Private       string
+		Params        string
+		Intermediates string
+		Final         string
+		Data          string
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		want fields
+	}{
+		{
+			name: "csi",
+			s:    "\x1b[?1;2 q",
+			want: fields{Kind: TokenCSI, Private: "?", Params: "1;2", Intermediates: " ", Final: "q"},
+		},
+		{
+			name: "sgr",
+			s:    "\x1b[38:2::1:2:3m",
+			want: fields{Kind: TokenCSI, Params: "38:2::1:2:3", Final: "m"},
+		},
+		{
+			name: "escape",
+			s:    "\x1b(B",
+			want: fields{Kind: TokenEscape, Intermediates: "(", Final: "B"},
+		},
+		{
+			name: "ss3",
+			s:    "\x1bOA",
+			want: fields{Kind: TokenSS3, Final: "A"},
+		},
+		{
+			name: "osc",
+			s:    "\x1b]8;;https://example.com\a",
+			want: fields{Kind: TokenOSC, Data: "8;;https://example.com"},
+		},
+		{
+			name: "dcs",
+			s:    "\x1bP1$r0m\x1b\\",
+			want: fields{Kind: TokenDCS, Params: "1", Intermediates: "$", Final: "r", Data: "0m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []fields
+			var tz Tokenizer
+			tz.Tokenize([]byte(tt.s), func(tok Token) {
+				f := fields{
+					Kind:          tok.Kind,
+					Params:        string(tok.Params),
+					Intermediates: string(tok.Intermediates),
+					Data:          string(tok.Data),
+				}
+				if tok.Private != 0 {
+					f.Private = string(tok.Private)
+				}
+				if tok.Final != 0 {
+					f.Final = string(tok.Final)
+				}
+				got = append(got, f)
+			})
+
+			if len(got) != 1 || got[0] != tt.want {
+				t.Fatalf("Tokenize() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToken_Param(t *testing.T) {
+	tests := []struct {
+		params string
+		i      int
+		def    int
+		want   int
+		num    int
+	}{
+		{params: "", i: 0, def: 1, want: 1, num: 0},
+		{params: "5", i: 0, def: 1, want: 5, num: 1},
+		{params: "5;12", i: 1, def: 1, want: 12, num: 2},
+		{params: "5;;7", i: 1, def: 1, want: 1, num: 3},
+		{params: "5", i: 1, def: 1, want: 1, num: 1},
+		{params: "4:3;1", i: 0, def: 0, want: 4, num: 2},
+		{params: "4:3;1", i: 1, def: 0, want: 1, num: 2},
+		{params: "0", i: 0, def: 1, want: 0, num: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.params, tt.i), func(t *testing.T) {
+			tok := Token{Kind: TokenCSI, Params: []byte(tt.params)}
+			if got := tok.Param(tt.i, tt.def); got != tt.want {
+				t.Fatalf("Param(%d, %d) = %d, expected %d", tt.i, tt.def, got, tt.want)
+			}
+			if got := tok.NumParams(); got != tt.num {
+				t.Fatalf("NumParams() = %d, expected %d", got, tt.num)
+			}
+		})
+	}
+}
+
+func TestSequenceLength(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "empty", s: "", want: 0},
+		{name: "text", s: "hello", want: 0},
+		{name: "csi", s: "\x1b[1;31mhello", want: 7},
+		{name: "osc bel", s: "\x1b]0;title\ahello", want: 10},
+		{name: "osc st", s: "\x1b]0;title\x1b\\hello", want: 11},
+		{name: "escape", s: "\x1b7hello", want: 2},
+		{name: "incomplete", s: "\x1b[1;3", want: 5},
+		{name: "restart", s: "\x1b[1\x1b[2m", want: 3},
+		{name: "string restart", s: "\x1b]0;t\x1b[2m", want: 5},
+		{name: "control", s: "\x1b[1\n2mhello", want: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SequenceLength(tt.s); got != tt.want {
+				t.Fatalf("SequenceLength() = %d, expected %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	p := []byte("\x1b[1;31merror\x1b[0m: \x1b]8;;https://example.com\x1b\\details\x1b]8;;\x1b\\ héllo wörld\r\n")
+	var t Tokenizer
+	b.SetBytes(int64(len(p)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t.Tokenize(p, func(Token) {})
+	}
+}
@@ -0,0 +1,22 @@
+package ansi
+
+import (
+	"github.com/heaths/go-console/internal/text"
+)
+
+// StringWidth gets the number of columns s would occupy in a terminal,
+// ignoring escape sequences and control characters.
+func StringWidth(s string) int {
+	width := 0
+	count := func(tok Token) {
+		if tok.Kind == TokenText {
+			width += text.StringWidth(string(tok.Raw))
+		}
+	}
+
+	var t Tokenizer
+	t.Tokenize([]byte(s), count)
+	t.Flush(count)
+
+	return width
+}
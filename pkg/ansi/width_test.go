@@ -0,0 +1,27 @@
+package ansi
+
+import "testing"
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "empty", s: "", want: 0},
+		{name: "text", s: "hello", want: 5},
+		{name: "wide", s: "日本", want: 4},
+		{name: "sgr", s: "\x1b[1;31mhello\x1b[0m", want: 5},
+		{name: "link", s: "\x1b]8;;https://example.com\x1b\\日本\x1b]8;;\x1b\\", want: 4},
+		{name: "controls", s: "a\r\nb\a", want: 2},
+		{name: "incomplete", s: "hello\x1b[3", want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Fatalf("StringWidth() = %d, expected %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+// Package asciicast records and replays terminal sessions in asciicast v2
+// format. See https://docs.asciinema.org/manual/asciicast/v2/.
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Version is the asciicast format version supported.
+const Version = 2
+
+// Header describes a recording and is the first line of an asciicast file.
+type Header struct {
+	Version       int               `json:"version"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	Duration      float64           `json:"duration,omitempty"`
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Command       string            `json:"command,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+}
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// Output is data written to the terminal.
+	Output EventType = "o"
+
+	// Input is data read from the terminal.
+	Input EventType = "i"
+
+	// Resize is the new size of the terminal formatted as "WIDTHxHEIGHT".
+	Resize EventType = "r"
+
+	// Marker is a named point in the recording.
+	Marker EventType = "m"
+)
+
+// Event is a line after the Header of an asciicast file.
+type Event struct {
+	// Time since the start of the recording.
+	Time time.Duration
+	Type EventType
+	Data string
+}
+
+// MarshalJSON encodes the Event as an array of time in seconds, type, and data.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{
+		json.Number(strconv.FormatFloat(e.Time.Seconds(), 'f', 6, 64)),
+		e.Type,
+		e.Data,
+	})
+}
+
+// UnmarshalJSON decodes an array of time in seconds, type, and data.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("expected 3 event fields, got %d", len(fields))
+	}
+
+	var seconds float64
+	if err := json.Unmarshal(fields[0], &seconds); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(fields[1], &e.Type); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(fields[2], &e.Data); err != nil {
+		return err
+	}
+
+	e.Time = time.Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// Size parses the width and height of a Resize event.
+func (e Event) Size() (width, height int, err error) {
+	if e.Type != Resize {
+		return 0, 0, fmt.Errorf("not a resize event: %q", e.Type)
+	}
+	if _, err := fmt.Sscanf(e.Data, "%dx%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("invalid resize event %q: %w", e.Data, err)
+	}
+	return
+}
+
+// Writer writes a recording in asciicast v2 format.
+type Writer struct {
+	w     io.Writer
+	start time.Time
+	now   func() time.Time
+
+	// Incomplete UTF-8 sequences by event type.
+	pending map[EventType][]byte
+	lock    sync.Mutex
+}
+
+// NewWriter writes the header to w and returns a Writer to record events
+// relative to now. The Version and, if not set, Timestamp are set.
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	return newWriter(w, header, time.Now)
+}
+
+func newWriter(w io.Writer, header Header, now func() time.Time) (*Writer, error) {
+	start := now()
+
+	header.Version = Version
+	if header.Timestamp == 0 {
+		header.Timestamp = start.Unix()
+	}
+
+	b, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		w:       w,
+		start:   start,
+		now:     now,
+		pending: make(map[EventType][]byte),
+	}, nil
+}
+
+// WriteOutput records data written to the terminal. Incomplete UTF-8
+// sequences are held until the rest is written.
+func (w *Writer) WriteOutput(p []byte) error {
+	return w.writeData(Output, p)
+}
+
+// WriteInput records data read from the terminal. Incomplete UTF-8 sequences
+// are held until the rest is read.
+func (w *Writer) WriteInput(p []byte) error {
+	return w.writeData(Input, p)
+}
+
+// WriteResize records the new size of the terminal.
+func (w *Writer) WriteResize(width, height int) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.write(Resize, fmt.Sprintf("%dx%d", width, height))
+}
+
+// WriteMarker records a named point in the recording.
+func (w *Writer) WriteMarker(label string) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.write(Marker, label)
+}
+
+// WriteEvent writes an Event as is.
+func (w *Writer) WriteEvent(e Event) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.writeEvent(e)
+}
+
+// Flush records any incomplete UTF-8 sequences held from WriteOutput or
+// WriteInput e.g., when recording stops. Invalid UTF-8 is recorded as the
+// replacement character.
+func (w *Writer) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, typ := range []EventType{Output, Input} {
+		if b := w.pending[typ]; len(b) > 0 {
+			delete(w.pending, typ)
+			if err := w.write(typ, string(b)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) writeData(typ EventType, p []byte) error {
+	// Hold the lock while writing so events are recorded in order.
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	b := append(w.pending[typ], p...)
+
+	// Hold back an incomplete UTF-8 sequence at the end.
+	i := len(b)
+	for j := len(b) - 1; j >= 0 && j >= len(b)-utf8.UTFMax; j-- {
+		if utf8.RuneStart(b[j]) {
+			if !utf8.FullRune(b[j:]) {
+				i = j
+			}
+			break
+		}
+	}
+
+	w.pending[typ] = append([]byte(nil), b[i:]...)
+	if i == 0 {
+		return nil
+	}
+
+	return w.write(typ, string(b[:i]))
+}
+
+// write writes an event of type typ that occurred now. The caller must hold
+// the lock.
+func (w *Writer) write(typ EventType, data string) error {
+	return w.writeEvent(Event{
+		Time: w.now().Sub(w.start),
+		Type: typ,
+		Data: data,
+	})
+}
+
+// writeEvent writes e. The caller must hold the lock.
+func (w *Writer) writeEvent(e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.w.Write(append(b, '\n'))
+	return err
+}
+
+// Reader reads a recording in asciicast v2 format.
+type Reader struct {
+	Header Header
+
+	s *bufio.Scanner
+}
+
+// NewReader reads the header from r and returns a Reader to read events.
+func NewReader(r io.Reader) (*Reader, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("missing asciicast header")
+	}
+
+	var header Header
+	if err := json.Unmarshal(s.Bytes(), &header); err != nil {
+		return nil, fmt.Errorf("invalid asciicast header: %w", err)
+	}
+	if header.Version != Version {
+		return nil, fmt.Errorf("unsupported asciicast version %d", header.Version)
+	}
+
+	return &Reader{
+		Header: header,
+		s:      s,
+	}, nil
+}
+
+// Next reads the next Event, or returns io.EOF if there are no more events.
+func (r *Reader) Next() (Event, error) {
+	for r.s.Scan() {
+		if len(r.s.Bytes()) == 0 {
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal(r.s.Bytes(), &e); err != nil {
+			return Event{}, fmt.Errorf("invalid asciicast event: %w", err)
+		}
+		return e, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
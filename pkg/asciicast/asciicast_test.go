@@ -0,0 +1,284 @@
+package asciicast
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func fakeNow(step time.Duration) func() time.Time {
+	now := time.Unix(1700000000, 0)
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := newWriter(buf, Header{Width: 80, Height: 24, Title: "test"}, fakeNow(500*time.Millisecond))
+	if err != nil {
+		t.Fatalf("newWriter() error = %v", err)
+	}
+
+	steps := []func() error{
+		func() error { return w.WriteOutput([]byte("hello\r\n")) },
+		func() error { return w.WriteInput([]byte("q")) },
+		func() error { return w.WriteResize(100, 30) },
+		func() error { return w.WriteMarker("done") },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	expected := `{"version":2,"width":80,"height":24,"timestamp":1700000000,"title":"test"}
+[0.500000,"o","hello\r\n"]
+[1.000000,"i","q"]
+[1.500000,"r","100x30"]
+[2.000000,"m","done"]
+`
+	if got := buf.String(); got != expected {
+		t.Fatalf("Writer wrote %q, expected %q", got, expected)
+	}
+}
+
+func TestWriter_utf8(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := newWriter(buf, Header{Width: 80, Height: 24}, fakeNow(time.Second))
+	if err != nil {
+		t.Fatalf("newWriter() error = %v", err)
+	}
+
+	// Split "é✓" across writes.
+	b := []byte("é✓")
+	for _, p := range [][]byte{b[:1], b[1:3], b[3:]} {
+		if err := w.WriteOutput(p); err != nil {
+			t.Fatalf("WriteOutput() error = %v", err)
+		}
+	}
+
+	r, err := NewReader(buf)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var data []string
+	for {
+		e, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		data = append(data, e.Data)
+	}
+
+	expected := []string{"é", "✓"}
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("Next() data = %q, expected %q", data, expected)
+	}
+}
+
+func TestWriter_Flush(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := newWriter(buf, Header{Width: 80, Height: 24}, fakeNow(time.Second))
+	if err != nil {
+		t.Fatalf("newWriter() error = %v", err)
+	}
+
+	// nolint:errcheck
+	w.WriteOutput([]byte("a\xe2\x9c"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	r, err := NewReader(buf)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var data []string
+	for {
+		e, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		data = append(data, e.Data)
+	}
+
+	expected := []string{"a", "\ufffd\ufffd"}
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("Next() data = %q, expected %q", data, expected)
+	}
+}
+
+func TestWriter_concurrent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := newWriter(buf, Header{Width: 80, Height: 24}, fakeNow(time.Millisecond))
+	if err != nil {
+		t.Fatalf("newWriter() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				// nolint:errcheck
+				if i%2 == 0 {
+					w.WriteOutput([]byte("x"))
+				} else {
+					w.WriteResize(80, 24)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	r, err := NewReader(buf)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	// Events should be recorded in order of time.
+	var last time.Duration
+	for {
+		e, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if e.Time <= last {
+			t.Fatalf("Next() time = %v after %v, expected increasing", e.Time, last)
+		}
+		last = e.Time
+	}
+}
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		header   Header
+		expected []Event
+		wantErr  string
+	}{
+		{
+			name: "events",
+			input: `{"version":2,"width":80,"height":24,"env":{"TERM":"xterm-256color"}}
+[0.1,"o","a"]
+
+[1.25,"r","100x30"]
+`,
+			header: Header{Version: 2, Width: 80, Height: 24, Env: map[string]string{"TERM": "xterm-256color"}},
+			expected: []Event{
+				{Time: 100 * time.Millisecond, Type: Output, Data: "a"},
+				{Time: 1250 * time.Millisecond, Type: Resize, Data: "100x30"},
+			},
+		},
+		{
+			name:    "empty",
+			wantErr: "missing asciicast header",
+		},
+		{
+			name:    "version",
+			input:   `{"version":1,"width":80,"height":24}`,
+			wantErr: "unsupported asciicast version 1",
+		},
+		{
+			name: "invalid event",
+			input: `{"version":2,"width":80,"height":24}
+[0.1,"o"]`,
+			header:  Header{Version: 2, Width: 80, Height: 24},
+			wantErr: "invalid asciicast event: expected 3 event fields, got 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(strings.NewReader(tt.input))
+			if err != nil {
+				if tt.wantErr == "" || err.Error() != tt.wantErr {
+					t.Fatalf("NewReader() error = %v, expected %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(r.Header, tt.header) {
+				t.Fatalf("Header = %+v, expected %+v", r.Header, tt.header)
+			}
+
+			var events []Event
+			for {
+				e, err := r.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					if tt.wantErr == "" || err.Error() != tt.wantErr {
+						t.Fatalf("Next() error = %v, expected %q", err, tt.wantErr)
+					}
+					return
+				}
+				events = append(events, e)
+			}
+
+			if tt.wantErr != "" {
+				t.Fatalf("expected error %q", tt.wantErr)
+			}
+			if !reflect.DeepEqual(events, tt.expected) {
+				t.Fatalf("Next() = %+v, expected %+v", events, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvent_Size(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   Event
+		width   int
+		height  int
+		wantErr bool
+	}{
+		{
+			name:   "resize",
+			event:  Event{Type: Resize, Data: "120x40"},
+			width:  120,
+			height: 40,
+		},
+		{
+			name:    "output",
+			event:   Event{Type: Output, Data: "120x40"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			event:   Event{Type: Resize, Data: "wide"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := tt.event.Size()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Size() error = %v, expected error %v", err, tt.wantErr)
+			}
+			if width != tt.width || height != tt.height {
+				t.Fatalf("Size() = %dx%d, expected %dx%d", width, height, tt.width, tt.height)
+			}
+		})
+	}
+}
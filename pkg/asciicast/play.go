@@ -0,0 +1,110 @@
+package asciicast
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// PlayOption configures Play.
+type PlayOption func(*player)
+
+type player struct {
+	speed     float64
+	idleLimit time.Duration
+	resize    func(width, height int)
+}
+
+// WithSpeed sets how fast to replay events relative to how they were
+// recorded, e.g. 2 replays twice as fast. A speed of 0 replays without
+// waiting between events.
+func WithSpeed(speed float64) PlayOption {
+	return func(p *player) {
+		p.speed = speed
+	}
+}
+
+// WithIdleTimeLimit sets the longest time to wait between events, overriding
+// the idle time limit in the header, if any.
+func WithIdleTimeLimit(limit time.Duration) PlayOption {
+	return func(p *player) {
+		p.idleLimit = limit
+	}
+}
+
+// WithResize sets a function called with the size of the terminal from the
+// header and every resize event.
+func WithResize(fn func(width, height int)) PlayOption {
+	return func(p *player) {
+		p.resize = fn
+	}
+}
+
+// Play reads a recording from r and writes output events to w, waiting
+// between events as they were recorded until done or ctx is canceled.
+// A Console can be passed as w to replay a recording onto it.
+func Play(ctx context.Context, r io.Reader, w io.Writer, opts ...PlayOption) error {
+	rd, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	p := &player{
+		speed:     1,
+		idleLimit: time.Duration(rd.Header.IdleTimeLimit * float64(time.Second)),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.resize != nil && rd.Header.Width > 0 && rd.Header.Height > 0 {
+		p.resize(rd.Header.Width, rd.Header.Height)
+	}
+
+	var last time.Duration
+	for {
+		e, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if p.speed > 0 {
+			delay := e.Time - last
+			if p.idleLimit > 0 && delay > p.idleLimit {
+				delay = p.idleLimit
+			}
+
+			if delay > 0 {
+				timer := time.NewTimer(time.Duration(float64(delay) / p.speed))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		last = e.Time
+
+		switch e.Type {
+		case Output:
+			if _, err := io.WriteString(w, e.Data); err != nil {
+				return err
+			}
+
+		case Resize:
+			if p.resize != nil {
+				width, height, err := e.Size()
+				if err != nil {
+					return err
+				}
+				p.resize(width, height)
+			}
+		}
+	}
+}
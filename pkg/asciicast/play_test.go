@@ -0,0 +1,81 @@
+package asciicast
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const recording = `{"version":2,"width":80,"height":24}
+[0.1,"o","hello"]
+[0.2,"r","100x30"]
+[0.3,"i","q"]
+[0.4,"o"," world"]
+[0.5,"m","end"]
+`
+
+func TestPlay(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var sizes []string
+	resize := func(width, height int) {
+		sizes = append(sizes, fmt.Sprintf("%dx%d", width, height))
+	}
+
+	err := Play(context.Background(), strings.NewReader(recording), buf, WithSpeed(0), WithResize(resize))
+	if err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+
+	if got := buf.String(); got != "hello world" {
+		t.Fatalf("Play() wrote %q, expected %q", got, "hello world")
+	}
+
+	expected := []string{"80x24", "100x30"}
+	if !reflect.DeepEqual(sizes, expected) {
+		t.Fatalf("Play() resized %q, expected %q", sizes, expected)
+	}
+}
+
+func TestPlay_idleTimeLimit(t *testing.T) {
+	input := `{"version":2,"width":80,"height":24,"idle_time_limit":0.01}
+[60,"o","a"]
+[120,"o","b"]
+`
+
+	buf := &bytes.Buffer{}
+	start := time.Now()
+	if err := Play(context.Background(), strings.NewReader(input), buf, WithSpeed(2)); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Play() took %v, expected idle time limit", elapsed)
+	}
+	if got := buf.String(); got != "ab" {
+		t.Fatalf("Play() wrote %q, expected %q", got, "ab")
+	}
+}
+
+func TestPlay_canceled(t *testing.T) {
+	input := `{"version":2,"width":80,"height":24}
+[0.1,"o","a"]
+[60,"o","b"]
+`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	buf := &bytes.Buffer{}
+	err := Play(ctx, strings.NewReader(input), buf)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Play() error = %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if got := buf.String(); got != "a" {
+		t.Fatalf("Play() wrote %q, expected %q", got, "a")
+	}
+}
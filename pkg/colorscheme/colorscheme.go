@@ -6,7 +6,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/heaths/go-console/internal/ansi"
+	"github.com/heaths/go-console/pkg/ansi"
 )
 
 const (
@@ -53,10 +53,16 @@ var (
 
 // ColorScheme formats text with different colors and styles.
 type ColorScheme struct {
-	colors map[string]func(string) string
-	isTTY  func() bool
+	colors  map[string]func(string) string
+	isTTY   func() bool
+	isLight func() bool
+	light   Theme
+	dark    Theme
 }
 
+// Theme maps names to styles supported by ColorFunc e.g., "error": "red+b".
+type Theme map[string]string
+
 type ColorSchemeOption func(*ColorScheme)
 
 // New creates a new ColorScheme with options like WithTTY.
@@ -78,8 +84,11 @@ func New(opts ...ColorSchemeOption) *ColorScheme {
 // Stdout is a TTY.
 func (cs *ColorScheme) Clone(opts ...ColorSchemeOption) *ColorScheme {
 	clone := &ColorScheme{
-		colors: cs.colors,
-		isTTY:  cs.isTTY,
+		colors:  cs.colors,
+		isTTY:   cs.isTTY,
+		isLight: cs.isLight,
+		light:   cs.light,
+		dark:    cs.dark,
 	}
 
 	for _, opt := range opts {
@@ -113,6 +122,45 @@ func (cs *ColorScheme) ColorFunc(style string) func(string) string {
 	return fn
 }
 
+// ThemeFunc returns a function to format text with the style of the given name
+// from the light or dark Theme depending on whether the background is light.
+// The dark Theme is used if no background function was set using
+// WithLightBackground.
+func (cs *ColorScheme) ThemeFunc(name string) func(string) string {
+	return cs.ThemeFuncOr(name, "")
+}
+
+// ThemeFuncOr is like ThemeFunc but returns a function to format text with
+// style if the Theme does not define name.
+func (cs *ColorScheme) ThemeFuncOr(name, style string) func(string) string {
+	theme := cs.dark
+	if cs.isLight != nil && cs.isLight() {
+		theme = cs.light
+	}
+
+	if s, ok := theme[name]; ok {
+		style = s
+	}
+
+	return cs.ColorFunc(style)
+}
+
+// WithThemes sets the light and dark Themes used by ThemeFunc.
+func WithThemes(light, dark Theme) ColorSchemeOption {
+	return func(cs *ColorScheme) {
+		cs.light = light
+		cs.dark = dark
+	}
+}
+
+// WithLightBackground sets a function for ColorScheme to determine if the
+// terminal background is light and select the light Theme in ThemeFunc.
+func WithLightBackground(isLight func() bool) ColorSchemeOption {
+	return func(cs *ColorScheme) {
+		cs.isLight = isLight
+	}
+}
+
 // WithTTY sets a function for ColorScheme to determine if the target Writer
 // represents a TTY and avoid writing terminal sequences.
 func WithTTY(isTTY func() bool) ColorSchemeOption {
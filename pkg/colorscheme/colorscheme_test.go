@@ -8,7 +8,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/heaths/go-console/internal/ansi"
+	"github.com/heaths/go-console/pkg/ansi"
 )
 
 func Benchmark_function(b *testing.B) {
@@ -342,6 +342,47 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestColorScheme_ThemeFunc(t *testing.T) {
+	light := Theme{"error": "red"}
+	dark := Theme{"error": "red+h"}
+
+	tests := []struct {
+		name    string
+		isLight func() bool
+		want    string
+	}{
+		{name: "default", want: "\x1b[0;91mtest\x1b[0m"},
+		{name: "dark", isLight: func() bool { return false }, want: "\x1b[0;91mtest\x1b[0m"},
+		{name: "light", isLight: alwaysTTY, want: "\x1b[0;31mtest\x1b[0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := New(WithTTY(alwaysTTY), WithThemes(light, dark), WithLightBackground(tt.isLight))
+			if got := cs.ThemeFunc("error")("test"); got != tt.want {
+				t.Fatalf("ThemeFunc()() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorScheme_ThemeFunc_missing(t *testing.T) {
+	cs := New(WithTTY(alwaysTTY))
+	if got := cs.ThemeFunc("error")("test"); got != "test" {
+		t.Fatalf("ThemeFunc()() = %q, expected %q", got, "test")
+	}
+}
+
+func TestColorScheme_ThemeFuncOr(t *testing.T) {
+	cs := New(WithTTY(alwaysTTY), WithThemes(nil, Theme{"error": "red"}))
+	if got, want := cs.ThemeFuncOr("error", "blue")("test"), "\x1b[0;31mtest\x1b[0m"; got != want {
+		t.Fatalf("ThemeFuncOr()() = %q, expected %q", got, want)
+	}
+	if got, want := cs.ThemeFuncOr("warning", "yellow")("test"), "\x1b[0;33mtest\x1b[0m"; got != want {
+		t.Fatalf("ThemeFuncOr()() = %q, expected %q", got, want)
+	}
+}
+
 func alwaysTTY() bool {
 	return true
 }
@@ -43,3 +43,27 @@ func ExampleColorScheme_color() {
 
 	// Output: \x1b[0;32mHello, world!\x1b[0m
 }
+
+func ExampleColorScheme_ThemeFunc() {
+	fake := console.Fake(
+		console.WithStdoutTTY(true),
+		console.WithBackground(0xfd, 0xf6, 0xe3),
+	)
+
+	cs := fake.ColorScheme().Clone(
+		colorscheme.WithThemes(
+			colorscheme.Theme{"heading": "blue+b"},
+			colorscheme.Theme{"heading": "cyan+bh"},
+		),
+	)
+
+	heading := cs.ThemeFunc("heading")
+	fmt.Fprintf(fake.Stdout(), "%s", heading("Hello, world!"))
+
+	// Doubly escape fake stdout and write to real stdout to assert output.
+	stdout, _, _ := fake.Buffers()
+	s := strings.ReplaceAll(stdout.String(), "\x1b", `\x1b`)
+	fmt.Println(s)
+
+	// Output: \x1b[0;1;34mHello, world!\x1b[0m
+}
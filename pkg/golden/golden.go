@@ -0,0 +1,151 @@
+// Package golden compares test output against golden files under testdata/.
+//
+// Run tests with -golden.update to write golden files from the current output,
+// or set GOLDEN_UPDATE=1 to update golden files for tests in packages that do
+// not all import golden:
+//
+//	go test . -golden.update
+//	GOLDEN_UPDATE=1 go test ./...
+package golden
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+var update = flag.Bool("golden.update", false, "update golden files")
+
+// updating returns whether golden files should be written instead of compared.
+func updating() bool {
+	if *update {
+		return true
+	}
+
+	v, _ := strconv.ParseBool(os.Getenv("GOLDEN_UPDATE"))
+	return v
+}
+
+// Assert compares got to the contents of testdata/name.golden, or writes got
+// to the golden file if the -golden.update flag or GOLDEN_UPDATE is set.
+// Escape sequences and other control characters are compared as written, and
+// are visualized in the diff reported on mismatch e.g., "␛[31m".
+func Assert(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("golden file %s not found; run tests with -golden.update to create it", path)
+		return
+	} else if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("output does not match %s (-want +got):\n%s", path, Diff(string(want), string(got)))
+	}
+}
+
+// AssertString compares got to the contents of testdata/name.golden like
+// Assert. Use it to compare a rendered screen e.g., FakeConsole.Screen().String().
+func AssertString(t testing.TB, name string, got string) {
+	t.Helper()
+	Assert(t, name, []byte(got))
+}
+
+// AssertText compares got with escape sequences removed to the contents of
+// testdata/name.golden like Assert.
+func AssertText(t testing.TB, name string, got []byte) {
+	t.Helper()
+	Assert(t, name, []byte(Strip(string(got))))
+}
+
+// Strip removes escape sequences from s.
+func Strip(s string) string {
+	return ansi.Strip(s)
+}
+
+// Visualize replaces control characters other than newlines with their
+// Unicode control pictures e.g., ESC with "␛" and carriage return with "␍".
+func Visualize(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			sb.WriteRune(r)
+		case r < 0x20:
+			sb.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			sb.WriteRune('␡')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+// Diff returns a line diff of want and got with control characters
+// visualized. Removed lines start with "-", added lines with "+", and
+// unchanged lines with a space.
+func Diff(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// Find the longest common subsequence of lines.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	line := func(prefix, s string) {
+		sb.WriteString(prefix + " " + Visualize(s) + "\n")
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			line(" ", a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			line("-", a[i])
+			i++
+		default:
+			line("+", b[j])
+			j++
+		}
+	}
+
+	return sb.String()
+}
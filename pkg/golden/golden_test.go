@@ -0,0 +1,183 @@
+package golden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recorder records errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	tests := []struct {
+		name      string
+		golden    string
+		got       string
+		assert    func(testing.TB, string, []byte)
+		wantError string
+	}{
+		{
+			name:   "raw",
+			golden: "raw",
+			got:    "\x1b[31mred\x1b[0m\nplain\n",
+			assert: Assert,
+		},
+		{
+			name:   "text",
+			golden: "text",
+			got:    "\x1b[31mred\x1b[0m\nplain\n",
+			assert: AssertText,
+		},
+		{
+			name:   "string",
+			golden: "text",
+			got:    "red\nplain\n",
+			assert: func(t testing.TB, name string, got []byte) {
+				AssertString(t, name, string(got))
+			},
+		},
+		{
+			name:      "mismatch",
+			golden:    "raw",
+			got:       "\x1b[32mred\x1b[0m\nplain\n",
+			assert:    Assert,
+			wantError: "output does not match testdata/raw.golden (-want +got):\n- ␛[31mred␛[0m\n+ ␛[32mred␛[0m\n  plain\n  \n",
+		},
+		{
+			name:      "missing",
+			golden:    "missing",
+			assert:    Assert,
+			wantError: "golden file testdata/missing.golden not found; run tests with -golden.update to create it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			tt.assert(r, tt.golden, []byte(tt.got))
+
+			got := strings.Join(r.errors, "\n")
+			if tt.wantError != "" {
+				// Paths use the OS separator.
+				got = strings.ReplaceAll(got, "testdata\\", "testdata/")
+			}
+			if got != tt.wantError {
+				t.Fatalf("Assert() error = %q, expected %q", got, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestAssert_update(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// nolint:errcheck
+		os.Chdir(wd)
+	})
+
+	t.Setenv("GOLDEN_UPDATE", "1")
+	Assert(t, "dir/new", []byte("hello\n"))
+
+	got, err := os.ReadFile(filepath.Join("testdata", "dir", "new.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello\n" {
+		t.Fatalf("golden file = %q, expected %q", got, "hello\n")
+	}
+}
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "hello", want: "hello"},
+		{name: "sgr", s: "\x1b[0;1;31mhello\x1b[0m", want: "hello"},
+		{name: "private", s: "\x1b[?25lhello\x1b[?25h", want: "hello"},
+		{name: "osc bel", s: "\x1b]0;title\ahello", want: "hello"},
+		{name: "osc st", s: "\x1b]8;;https://example.com\x1b\\hello\x1b]8;;\x1b\\", want: "hello"},
+		{name: "escape", s: "\x1b7hello\x1b8", want: "hello"},
+		{name: "incomplete", s: "hello\x1b[3", want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strip(tt.s); got != tt.want {
+				t.Fatalf("Strip() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisualize(t *testing.T) {
+	want := "␛[31mred␛[0m␍\n␉tab␇␡"
+	if got := Visualize("\x1b[31mred\x1b[0m\r\n\ttab\a\x7f"); got != want {
+		t.Fatalf("Visualize() = %q, expected %q", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{
+			name: "equal",
+			want: "a\nb",
+			got:  "a\nb",
+			diff: "  a\n  b\n",
+		},
+		{
+			name: "changed",
+			want: "a\nb\nc",
+			got:  "a\nx\nc",
+			diff: "  a\n- b\n+ x\n  c\n",
+		},
+		{
+			name: "added",
+			want: "a\nc",
+			got:  "a\nb\nc",
+			diff: "  a\n+ b\n  c\n",
+		},
+		{
+			name: "removed",
+			want: "a\nb\nc",
+			got:  "a\nc",
+			diff: "  a\n- b\n  c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.want, tt.got); got != tt.diff {
+				t.Fatalf("Diff() = %q, expected %q", got, tt.diff)
+			}
+		})
+	}
+}
@@ -21,6 +21,13 @@ func (c *con) StartProgress(label string, opts ...ProgressOption) {
 		return
 	}
 
+	// Write each frame of progress at once and, if already known to be
+	// supported, synchronized. Do not query the terminal just for progress.
+	fw := &frameWriter{
+		w:            c.recorded(c.stderr),
+		synchronized: c.knownSynchronizedOutput(),
+	}
+
 	c.progressLock.Lock()
 	defer c.progressLock.Unlock()
 
@@ -28,7 +35,7 @@ func (c *con) StartProgress(label string, opts ...ProgressOption) {
 	sp := spinner.New(
 		cs,
 		120*time.Millisecond,
-		spinner.WithWriter(c.stderr),
+		spinner.WithWriter(fw),
 
 		// TODO: Allow specifying another color.
 		spinner.WithColor("fgCyan"),
@@ -42,8 +49,14 @@ func (c *con) StartProgress(label string, opts ...ProgressOption) {
 		opt(c, sp)
 	}
 
+	sp.PostUpdate = func(*spinner.Spinner) {
+		// nolint:errcheck
+		fw.Flush()
+	}
+
 	sp.Start()
 	c.progress = sp
+	c.progressWriter = fw
 }
 
 func (c *con) StopProgress() {
@@ -61,6 +74,10 @@ func (c *con) StopProgress() {
 
 	c.progress.Stop()
 	c.progress = nil
+
+	// nolint:errcheck
+	c.progressWriter.Flush()
+	c.progressWriter = nil
 }
 
 func WithMinimum(d time.Duration) ProgressOption {
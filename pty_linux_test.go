@@ -0,0 +1,206 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heaths/go-console/internal/ptytest"
+)
+
+// testPTY is a Console attached to a pseudo-terminal.
+type testPTY struct {
+	*ptytest.PTY
+	con *con
+}
+
+func openPTY(t *testing.T) *testPTY {
+	t.Helper()
+
+	// Colors are disabled if TERM is "dumb".
+	t.Setenv("TERM", "xterm-256color")
+
+	p, err := ptytest.Open(defaultWidth, defaultHeight)
+	if err != nil {
+		t.Skipf("Open() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := p.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+
+	tty := p.TTY()
+	return &testPTY{PTY: p, con: newCon(tty, tty, tty)}
+}
+
+func (p *testPTY) Console() Console {
+	return p.con
+}
+
+// Screen renders everything the Console has written so far onto a new Screen
+// the current size of the terminal.
+func (p *testPTY) Screen() *Screen {
+	width, height, err := p.con.Size()
+	if err != nil {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	s := NewScreen(width, height)
+	// nolint:errcheck
+	s.Write([]byte(p.Output()))
+
+	return s
+}
+
+// waitFor waits for output containing s.
+func waitFor(t *testing.T, p *testPTY, s string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(p.Output(), s) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Output() = %q, expected to contain %q", p.Output(), s)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPTY_TTY(t *testing.T) {
+	p := openPTY(t)
+	con := p.Console()
+
+	if !con.IsStdinTTY() || !con.IsStdoutTTY() || !con.IsStderrTTY() {
+		t.Fatal("Console() is not a terminal")
+	}
+
+	width, height, err := con.Size()
+	if err != nil {
+		t.Fatalf("Size() error = %v", err)
+	}
+	if width != 80 || height != 24 {
+		t.Fatalf("Size() = (%d, %d), expected (80, 24)", width, height)
+	}
+
+	if err := p.Resize(100, 40); err != nil {
+		t.Fatalf("Resize() error = %v", err)
+	}
+	if width, height, _ = con.Size(); width != 100 || height != 40 {
+		t.Fatalf("Size() = (%d, %d), expected (100, 40)", width, height)
+	}
+}
+
+func TestPTY_Output(t *testing.T) {
+	p := openPTY(t)
+	con := p.Console()
+
+	fmt.Fprintln(con.Stdout(), con.ColorScheme().Green("hello"))
+
+	// The line discipline translates newlines.
+	waitFor(t, p, "\x1b[0;32mhello\x1b[0m\r\n")
+	if got := p.Screen().Lines()[0]; got != "hello" {
+		t.Fatalf("Screen() = %q, expected %q", got, "hello")
+	}
+}
+
+func TestPTY_RawMode(t *testing.T) {
+	p := openPTY(t)
+	con := p.Console()
+
+	if err := con.EnableRawMode(); err != nil {
+		t.Fatalf("EnableRawMode() error = %v", err)
+	}
+
+	// Input is not echoed in raw mode.
+	fmt.Fprint(p, "x")
+	ev, err := con.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent() error = %v", err)
+	}
+	if want := (KeyEvent{Key: KeyRune, Rune: 'x'}); ev != want {
+		t.Fatalf("ReadEvent() = %v, expected %v", ev, want)
+	}
+
+	if err := con.DisableRawMode(); err != nil {
+		t.Fatalf("DisableRawMode() error = %v", err)
+	}
+
+	// Input is echoed by the line discipline again.
+	fmt.Fprint(p, "echo\n")
+	waitFor(t, p, "echo\r\n")
+}
+
+func TestPTY_ReadLine(t *testing.T) {
+	p := openPTY(t)
+	con := p.Console()
+
+	result := make(chan string)
+	go func() {
+		line, err := con.ReadLine("> ")
+		if err != nil {
+			line = err.Error()
+		}
+		result <- line
+	}()
+
+	waitFor(t, p, "> ")
+	fmt.Fprint(p, "helo\x1b[Dl\r")
+
+	select {
+	case got := <-result:
+		if got != "hello" {
+			t.Fatalf("ReadLine() = %q, expected %q", got, "hello")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ReadLine() timed out")
+	}
+
+	waitFor(t, p, "\r\n")
+	if got := p.Screen().Lines()[0]; got != "> hello" {
+		t.Fatalf("Screen() = %q, expected %q", got, "> hello")
+	}
+}
+
+func TestPTY_suspend(t *testing.T) {
+	p := openPTY(t)
+	c := p.Console().(*con)
+
+	if err := c.EnableRawMode(); err != nil {
+		t.Fatalf("EnableRawMode() error = %v", err)
+	}
+
+	// A lone escape times out, leaving a read pending.
+	fmt.Fprint(p, "\x1b")
+	if ev, err := c.ReadEvent(); err != nil || ev != (KeyEvent{Key: KeyEscape}) {
+		t.Fatalf("ReadEvent() = %v, %v, expected Escape", ev, err)
+	}
+
+	// Another process should read input while suspended.
+	resume := c.suspend()
+	fmt.Fprint(p, "child\n")
+
+	read := make(chan string, 1)
+	go func() {
+		b := make([]byte, 16)
+		n, _ := c.stdin.(*os.File).Read(b)
+		read <- string(b[:n])
+	}()
+
+	select {
+	case got := <-read:
+		if got != "child\n" {
+			t.Fatalf("Read() = %q, expected %q", got, "child\n")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("input was read by the console while suspended")
+	}
+
+	resume()
+
+	fmt.Fprint(p, "x")
+	if ev, err := c.ReadEvent(); err != nil || ev != (KeyEvent{Key: KeyRune, Rune: 'x'}) {
+		t.Fatalf("ReadEvent() = %v, %v, expected x", ev, err)
+	}
+}
@@ -0,0 +1,658 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/heaths/go-console/internal/text"
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// ErrInterrupted is returned when the user presses Ctrl+C while reading input.
+var ErrInterrupted = errors.New("interrupted")
+
+// CompletionFunc returns candidates to replace line[start:pos], where pos is
+// the byte offset of the cursor within line.
+type CompletionFunc func(line string, pos int) (candidates []string, start int)
+
+// LineOption configures ReadLine.
+type LineOption func(*lineEditor)
+
+// WithHistory sets the History to navigate using the up and down arrows, and
+// search using Ctrl+R. Lines read are added to the History.
+func WithHistory(h *History) LineOption {
+	return func(e *lineEditor) {
+		e.history = h
+	}
+}
+
+// WithCompletion sets the function to complete the line when Tab is pressed.
+func WithCompletion(fn CompletionFunc) LineOption {
+	return func(e *lineEditor) {
+		e.completion = fn
+	}
+}
+
+type lineEditor struct {
+	c          *con
+	prompt     string
+	history    *History
+	completion CompletionFunc
+
+	buf []rune
+	pos int
+
+	// Rows drawn and the row of the cursor from the first row, which may wrap
+	// when the prompt and line are wider than the terminal.
+	rows        int
+	cursorRow   int
+	promptWidth int
+
+	// History navigation.
+	entries   []string
+	histIndex int
+	saved     []rune
+
+	// Reverse search.
+	searching   bool
+	query       []rune
+	searchIndex int
+	searchFound bool
+	searchBuf   []rune
+	searchPos   int
+}
+
+// ReadLine writes the prompt and reads a line of input with support for
+// Emacs key bindings, History, and completion. If Stdin or Stdout is not a
+// terminal, a line is read from Stdin without editing.
+//
+// ReadLine returns ErrInterrupted if Ctrl+C is pressed, or io.EOF if Ctrl+D is
+// pressed on an empty line.
+func (c *con) ReadLine(prompt string, opts ...LineOption) (string, error) {
+	e := &lineEditor{
+		c:      c,
+		prompt: prompt,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	var line string
+	var err error
+	if c.IsStdinTTY() && c.IsStdoutTTY() {
+		line, err = e.edit()
+	} else {
+		if c.IsStdoutTTY() {
+			fmt.Fprint(c.out(), prompt)
+		}
+		line, err = c.readLine()
+	}
+
+	if err == nil && e.history != nil {
+		err = e.history.Add(line)
+	}
+
+	return line, err
+}
+
+// readLine reads a line from Stdin without editing.
+func (c *con) readLine() (string, error) {
+	c.inputLock.Lock()
+	defer c.inputLock.Unlock()
+
+	in := c.reader()
+	for {
+		in.waitForQuery()
+		if i := bytes.IndexByte(in.buf, '\n'); i >= 0 {
+			line := string(bytes.TrimSuffix(in.buf[:i], []byte{'\r'}))
+			in.buf = in.buf[i+1:]
+			return line, nil
+		}
+
+		if err := in.fill(-1); err != nil {
+			if errors.Is(err, io.EOF) && len(in.buf) > 0 {
+				line := string(in.buf)
+				in.buf = nil
+				return line, nil
+			}
+
+			return "", err
+		}
+	}
+}
+
+func (e *lineEditor) edit() (string, error) {
+	restore, err := e.c.makeRaw()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	e.c.modesLock.Lock()
+	paste := e.c.modes.paste
+	e.c.modesLock.Unlock()
+
+	if !paste {
+		e.c.EnableBracketedPaste()
+		defer e.c.DisableBracketedPaste()
+	}
+
+	if e.history != nil {
+		e.entries = e.history.Entries()
+	}
+	e.histIndex = len(e.entries)
+
+	e.refresh()
+	for {
+		ev, err := e.c.ReadEvent()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(e.buf) > 0 {
+				e.finish()
+				return string(e.buf), nil
+			}
+
+			return "", err
+		}
+
+		switch ev := ev.(type) {
+		case PasteEvent:
+			e.endSearch()
+			e.insert([]rune(strings.ReplaceAll(ev.Text, "\n", " ")))
+
+		case KeyEvent:
+			if ev.Action == KeyRelease {
+				continue
+			}
+
+			if e.searching && e.search(ev) {
+				continue
+			}
+
+			if done, err := e.handleKey(ev); done {
+				if err != nil {
+					return "", err
+				}
+				return string(e.buf), nil
+			}
+		}
+	}
+}
+
+// handleKey handles a key and returns true if editing is done.
+func (e *lineEditor) handleKey(ev KeyEvent) (bool, error) {
+	ctrl := ev.Modifiers&ModCtrl != 0
+	alt := ev.Modifiers&ModAlt != 0
+
+	switch ev.Key {
+	case KeyEnter:
+		e.finish()
+		return true, nil
+
+	case KeyTab:
+		if ev.Modifiers == 0 {
+			e.complete()
+		}
+
+	case KeyBackspace:
+		if alt {
+			e.deleteWordBack()
+		} else {
+			e.backspace()
+		}
+
+	case KeyDelete:
+		e.delete()
+
+	case KeyLeft:
+		if ctrl || alt {
+			e.moveTo(e.wordStart())
+		} else {
+			e.moveTo(e.pos - 1)
+		}
+
+	case KeyRight:
+		if ctrl || alt {
+			e.moveTo(e.wordEnd())
+		} else {
+			e.moveTo(e.pos + 1)
+		}
+
+	case KeyHome:
+		e.moveTo(0)
+
+	case KeyEnd:
+		e.moveTo(len(e.buf))
+
+	case KeyUp:
+		e.previous()
+
+	case KeyDown:
+		e.next()
+
+	case KeyRune:
+		switch {
+		case ctrl:
+			return e.handleCtrl(ev.Rune)
+
+		case alt:
+			switch ev.Rune {
+			case 'b':
+				e.moveTo(e.wordStart())
+			case 'f':
+				e.moveTo(e.wordEnd())
+			case 'd':
+				e.deleteWordForward()
+			}
+
+		default:
+			e.insert([]rune{ev.Rune})
+		}
+	}
+
+	return false, nil
+}
+
+func (e *lineEditor) handleCtrl(r rune) (bool, error) {
+	switch r {
+	case 'a':
+		e.moveTo(0)
+	case 'b':
+		e.moveTo(e.pos - 1)
+	case 'c':
+		e.moveTo(len(e.buf))
+		fmt.Fprint(e.c.out(), "^C\r\n")
+		return true, ErrInterrupted
+	case 'd':
+		if len(e.buf) == 0 {
+			fmt.Fprint(e.c.out(), "\r\n")
+			return true, io.EOF
+		}
+		e.delete()
+	case 'e':
+		e.moveTo(len(e.buf))
+	case 'f':
+		e.moveTo(e.pos + 1)
+	case 'h':
+		e.backspace()
+	case 'k':
+		e.buf = e.buf[:e.pos]
+		e.refresh()
+	case 'l':
+		e.c.ClearScreen()
+		e.rows, e.cursorRow = 0, 0
+		e.refresh()
+	case 'n':
+		e.next()
+	case 'p':
+		e.previous()
+	case 'r':
+		e.startSearch()
+	case 'u':
+		e.buf = append([]rune(nil), e.buf[e.pos:]...)
+		e.pos = 0
+		e.refresh()
+	case 'w':
+		e.deleteWordBack()
+	}
+
+	return false, nil
+}
+
+// finish moves the cursor to the end of the line and starts a new line.
+func (e *lineEditor) finish() {
+	e.moveTo(len(e.buf))
+	fmt.Fprint(e.c.out(), "\r\n")
+}
+
+// refresh redraws the prompt and line, and positions the cursor.
+func (e *lineEditor) refresh() {
+	prompt := e.prompt
+	if e.searching {
+		prefix := "(reverse-i-search)"
+		if !e.searchFound {
+			prefix = "(failed reverse-i-search)"
+		}
+		prompt = fmt.Sprintf("%s`%s': ", prefix, string(e.query))
+	}
+
+	// Clear all rows previously drawn from the first row.
+	if e.cursorRow > 0 {
+		e.c.CursorUp(e.cursorRow)
+	}
+	e.c.CursorColumn(1)
+	if e.rows > 1 {
+		e.c.ClearLinesDown(e.rows)
+	} else {
+		e.c.ClearLine()
+	}
+	fmt.Fprint(e.c.out(), prompt+string(e.buf))
+
+	width := e.width()
+	promptWidth := ansi.StringWidth(prompt)
+	end := promptWidth + text.RunesWidth(e.buf)
+	if end > 0 && end%width == 0 {
+		// Move to the next row since the cursor stays in the last column.
+		fmt.Fprint(e.c.out(), "\r\n")
+	}
+
+	e.rows = end/width + 1
+	e.cursorRow = end / width
+	e.promptWidth = promptWidth
+	e.moveCursor(end, promptWidth+text.RunesWidth(e.buf[:e.pos]))
+}
+
+// moveTo moves the cursor to pos without redrawing the line.
+func (e *lineEditor) moveTo(pos int) {
+	pos = clamp(pos, 0, len(e.buf))
+	from := e.promptWidth + text.RunesWidth(e.buf[:e.pos])
+	e.moveCursor(from, e.promptWidth+text.RunesWidth(e.buf[:pos]))
+	e.pos = pos
+}
+
+// moveCursor moves the cursor between columns from the start of the prompt,
+// which may be on different rows if the line wraps.
+func (e *lineEditor) moveCursor(from, to int) {
+	width := e.width()
+	fromRow, fromColumn := from/width, from%width
+	toRow, toColumn := to/width, to%width
+
+	if toRow < fromRow {
+		e.c.CursorUp(fromRow - toRow)
+	} else if toRow > fromRow {
+		e.c.CursorDown(toRow - fromRow)
+	}
+
+	if toColumn < fromColumn {
+		e.c.CursorBack(fromColumn - toColumn)
+	} else if toColumn > fromColumn {
+		e.c.CursorForward(toColumn - fromColumn)
+	}
+
+	e.cursorRow = toRow
+}
+
+// width gets the width of the terminal.
+func (e *lineEditor) width() int {
+	width, _, err := e.c.Size()
+	if err != nil || width <= 0 {
+		return defaultWidth
+	}
+
+	return width
+}
+
+func (e *lineEditor) insert(runes []rune) {
+	buf := make([]rune, 0, len(e.buf)+len(runes))
+	buf = append(buf, e.buf[:e.pos]...)
+	buf = append(buf, runes...)
+	buf = append(buf, e.buf[e.pos:]...)
+
+	e.buf = buf
+	e.pos += len(runes)
+	e.refresh()
+}
+
+// remove removes runes between from and to, and moves the cursor to from.
+func (e *lineEditor) remove(from, to int) {
+	if from >= to {
+		return
+	}
+
+	buf := make([]rune, 0, len(e.buf)-(to-from))
+	buf = append(buf, e.buf[:from]...)
+	e.buf = append(buf, e.buf[to:]...)
+	e.pos = from
+	e.refresh()
+}
+
+func (e *lineEditor) backspace() {
+	if e.pos > 0 {
+		e.remove(e.pos-1, e.pos)
+	}
+}
+
+func (e *lineEditor) delete() {
+	if e.pos < len(e.buf) {
+		e.remove(e.pos, e.pos+1)
+	}
+}
+
+func (e *lineEditor) deleteWordBack() {
+	e.remove(e.wordStart(), e.pos)
+}
+
+func (e *lineEditor) deleteWordForward() {
+	end := e.wordEnd()
+	e.remove(e.pos, end)
+}
+
+// wordStart gets the start of the word before the cursor.
+func (e *lineEditor) wordStart() int {
+	i := e.pos
+	for i > 0 && unicode.IsSpace(e.buf[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(e.buf[i-1]) {
+		i--
+	}
+
+	return i
+}
+
+// wordEnd gets the end of the word after the cursor.
+func (e *lineEditor) wordEnd() int {
+	i := e.pos
+	for i < len(e.buf) && unicode.IsSpace(e.buf[i]) {
+		i++
+	}
+	for i < len(e.buf) && !unicode.IsSpace(e.buf[i]) {
+		i++
+	}
+
+	return i
+}
+
+func (e *lineEditor) previous() {
+	if e.histIndex == 0 {
+		return
+	}
+
+	if e.histIndex == len(e.entries) {
+		e.saved = e.buf
+	}
+
+	e.histIndex--
+	e.setLine([]rune(e.entries[e.histIndex]))
+}
+
+func (e *lineEditor) next() {
+	if e.histIndex >= len(e.entries) {
+		return
+	}
+
+	e.histIndex++
+	if e.histIndex == len(e.entries) {
+		e.setLine(e.saved)
+	} else {
+		e.setLine([]rune(e.entries[e.histIndex]))
+	}
+}
+
+func (e *lineEditor) setLine(line []rune) {
+	e.buf = append([]rune(nil), line...)
+	e.pos = len(e.buf)
+	e.refresh()
+}
+
+func (e *lineEditor) startSearch() {
+	if !e.searching {
+		e.searching = true
+		e.query = nil
+		e.searchIndex = len(e.entries)
+		e.searchFound = true
+		e.searchBuf = e.buf
+		e.searchPos = e.pos
+		e.refresh()
+		return
+	}
+
+	// Find the next older match.
+	e.find(e.searchIndex - 1)
+}
+
+// find searches for the query from entry i toward older entries.
+func (e *lineEditor) find(i int) {
+	query := string(e.query)
+	if i >= len(e.entries) {
+		i = len(e.entries) - 1
+	}
+
+	for ; i >= 0; i-- {
+		if j := strings.Index(e.entries[i], query); j >= 0 {
+			e.searchIndex = i
+			e.searchFound = true
+			e.buf = []rune(e.entries[i])
+			e.pos = len([]rune(e.entries[i][:j]))
+			e.refresh()
+			return
+		}
+	}
+
+	e.searchFound = false
+	e.refresh()
+}
+
+// search handles a key during reverse search and returns true if handled.
+func (e *lineEditor) search(ev KeyEvent) bool {
+	switch {
+	case ev.Key == KeyRune && ev.Modifiers == 0:
+		e.query = append(e.query, ev.Rune)
+		e.find(e.searchIndex)
+		return true
+
+	case ev.Key == KeyBackspace:
+		if len(e.query) > 0 {
+			e.query = e.query[:len(e.query)-1]
+		}
+		e.find(len(e.entries) - 1)
+		return true
+
+	case ev.Key == KeyRune && ev.Modifiers == ModCtrl && ev.Rune == 'r':
+		e.startSearch()
+		return true
+
+	case ev.Key == KeyEscape || ev.Key == KeyRune && ev.Modifiers == ModCtrl && ev.Rune == 'g':
+		e.searching = false
+		e.buf, e.pos = e.searchBuf, e.searchPos
+		e.refresh()
+		return true
+	}
+
+	// Accept the match and handle the key normally.
+	e.endSearch()
+	return false
+}
+
+func (e *lineEditor) endSearch() {
+	if e.searching {
+		e.searching = false
+		if e.searchIndex < len(e.entries) {
+			e.histIndex = e.searchIndex
+		}
+		e.refresh()
+	}
+}
+
+func (e *lineEditor) complete() {
+	if e.completion == nil {
+		return
+	}
+
+	line := string(e.buf)
+	pos := len(string(e.buf[:e.pos]))
+
+	candidates, start := e.completion(line, pos)
+	if len(candidates) == 0 || start < 0 || start > pos {
+		return
+	}
+
+	replace := func(s string) {
+		e.buf = []rune(line[:start] + s + line[pos:])
+		e.pos = len([]rune(line[:start] + s))
+		e.refresh()
+	}
+
+	if len(candidates) == 1 {
+		replace(candidates[0])
+		return
+	}
+
+	if prefix := commonPrefix(candidates); len(prefix) > pos-start {
+		replace(prefix)
+		return
+	}
+
+	e.showCandidates(candidates)
+}
+
+// showCandidates writes candidates in columns below the line and redraws it.
+func (e *lineEditor) showCandidates(candidates []string) {
+	width := e.width()
+
+	columnWidth := 0
+	for _, c := range candidates {
+		if w := text.StringWidth(c); w > columnWidth {
+			columnWidth = w
+		}
+	}
+	columnWidth += 2
+
+	columns := width / columnWidth
+	if columns < 1 {
+		columns = 1
+	}
+	rows := (len(candidates) + columns - 1) / columns
+
+	var sb strings.Builder
+	sb.WriteString("\r\n")
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			i := column*rows + row
+			if i >= len(candidates) {
+				break
+			}
+
+			c := candidates[i]
+			sb.WriteString(c)
+			if column < columns-1 && i+rows < len(candidates) {
+				sb.WriteString(strings.Repeat(" ", columnWidth-text.StringWidth(c)))
+			}
+		}
+		sb.WriteString("\r\n")
+	}
+
+	e.moveTo(len(e.buf))
+	fmt.Fprint(e.c.out(), sb.String())
+
+	// Redraw the line below the candidates.
+	e.rows, e.cursorRow = 0, 0
+	e.refresh()
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+
+	return prefix
+}
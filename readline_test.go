@@ -0,0 +1,319 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConsole_ReadLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+		screen  string
+	}{
+		{
+			name:   "text",
+			input:  "hello\r",
+			want:   "hello",
+			screen: "> hello",
+		},
+		{
+			name:   "eof",
+			input:  "hello",
+			want:   "hello",
+			screen: "> hello",
+		},
+		{
+			name:   "insert",
+			input:  "helo\x1b[D\x1b[Dl\r",
+			want:   "hello",
+			screen: "> hello",
+		},
+		{
+			name:   "backspace and delete",
+			input:  "helxlo\x1b[D\x1b[D\x7f\x1b[3~\r",
+			want:   "helo",
+			screen: "> helo",
+		},
+		{
+			name:   "home and end",
+			input:  "ello\x01h\x05!\r",
+			want:   "hello!",
+			screen: "> hello!",
+		},
+		{
+			name:  "kill",
+			input: "hello world\x02\x02\x0b\x01\x06\x15\r",
+			want:  "ello wor",
+		},
+		{
+			name:  "kill word",
+			input: "hello big world\x17\x17there\r",
+			want:  "hello there",
+		},
+		{
+			name:  "word movement",
+			input: "one two three\x1bb\x1bb\x1bd\x1bfX\r",
+			want:  "one  threeX",
+		},
+		{
+			name:   "wide characters",
+			input:  "世界\x1b[D\x1b[D你好\r",
+			want:   "你好世界",
+			screen: "> 你好世界",
+		},
+		{
+			name:   "paste",
+			input:  "\x1b[200~one\r\ntwo\x1b[201~\r",
+			want:   "one two",
+			screen: "> one two",
+		},
+		{
+			name:    "interrupt",
+			input:   "hello\x03",
+			wantErr: ErrInterrupted,
+			screen:  "> hello^C",
+		},
+		{
+			name:    "end of input",
+			input:   "\x04",
+			wantErr: io.EOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+				WithSize(40, 5),
+			)
+
+			got, err := f.ReadLine("> ")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadLine() error = %v, expected %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ReadLine() = %q, expected %q", got, tt.want)
+			}
+
+			if tt.screen != "" {
+				if got := f.Screen().Lines()[0]; got != tt.screen {
+					t.Fatalf("Screen() = %q, expected %q", got, tt.screen)
+				}
+			}
+
+			if f.isRaw() {
+				t.Fatal("ReadLine() did not disable raw mode")
+			}
+		})
+	}
+}
+
+func TestConsole_ReadLine_wrapped(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		screen []string
+	}{
+		{
+			name:   "insert at start",
+			input:  "abcdefghijklmnopqrstuvwxyz\x01X\r",
+			want:   "Xabcdefghijklmnopqrstuvwxyz",
+			screen: []string{"> Xabcdefghijklmnopq", "rstuvwxyz", ""},
+		},
+		{
+			name:   "backspace across rows",
+			input:  "abcdefghijklmnopqrstuvwxyz\x01\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x7f\x7fX\r",
+			want:   "abcdefghijklmnopqXtuvwxyz",
+			screen: []string{"> abcdefghijklmnopqX", "tuvwxyz", ""},
+		},
+		{
+			name:   "shrink",
+			input:  "abcdefghijklmnopqrstuvwxyz\x15hello\r",
+			want:   "hello",
+			screen: []string{"> hello", "", ""},
+		},
+		{
+			name:   "exact width",
+			input:  "abcdefghijklmnopqr\x1b[D\x1b[DX\x05!\r",
+			want:   "abcdefghijklmnopXqr!",
+			screen: []string{"> abcdefghijklmnopXq", "r!", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+				WithSize(20, 5),
+			)
+
+			got, err := f.ReadLine("> ")
+			if err != nil {
+				t.Fatalf("ReadLine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ReadLine() = %q, expected %q", got, tt.want)
+			}
+
+			if got := f.Screen().Lines()[:len(tt.screen)]; !reflect.DeepEqual(got, tt.screen) {
+				t.Fatalf("Screen() = %q, expected %q", got, tt.screen)
+			}
+		})
+	}
+}
+
+func TestConsole_ReadLine_notTTY(t *testing.T) {
+	f := Fake(
+		WithStdin(bytes.NewBufferString("one\r\ntwo\nthree")),
+	)
+
+	var got []string
+	for {
+		line, err := f.ReadLine("> ")
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("ReadLine() error = %v", err)
+		}
+		got = append(got, line)
+	}
+
+	if want := []string{"one", "two", "three"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReadLine() = %q, expected %q", got, want)
+	}
+
+	stdout, _, _ := f.Buffers()
+	if stdout.Len() > 0 {
+		t.Fatalf("ReadLine() wrote %q, expected nothing", stdout.String())
+	}
+}
+
+func TestConsole_ReadLine_history(t *testing.T) {
+	h, _ := NewHistory("")
+	for _, line := range []string{"first", "second", "third"} {
+		h.Add(line) // nolint:errcheck
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "previous", input: "\x1b[A\x1b[A\r", want: "second"},
+		{name: "next", input: "draft\x1b[A\x1b[A\x1b[B\x1b[B\r", want: "draft"},
+		{name: "oldest", input: "\x10\x10\x10\x10\x10\r", want: "first"},
+		{name: "search", input: "\x12fi\r", want: "first"},
+		{name: "search older", input: "\x12d\x12\r", want: "second"},
+		{name: "search edit", input: "\x12sec\x05!\r", want: "second!"},
+		{name: "search cancel", input: "draft\x12th\x07\r", want: "draft"},
+		{name: "search failed", input: "\x12thx\r", want: "third"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+			)
+
+			h := &History{entries: h.Entries()}
+			got, err := f.ReadLine("> ", WithHistory(h))
+			if err != nil {
+				t.Fatalf("ReadLine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ReadLine() = %q, expected %q", got, tt.want)
+			}
+
+			if entries := h.Entries(); entries[len(entries)-1] != tt.want {
+				t.Fatalf("History.Entries() = %q, expected %q last", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_ReadLine_completion(t *testing.T) {
+	commands := []string{"checkout", "cherry-pick", "clone", "commit"}
+	complete := func(line string, pos int) ([]string, int) {
+		start := strings.LastIndexByte(line[:pos], ' ') + 1
+		var candidates []string
+		for _, c := range commands {
+			if strings.HasPrefix(c, line[start:pos]) {
+				candidates = append(candidates, c)
+			}
+		}
+		return candidates, start
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		screen []string
+	}{
+		{
+			name:  "single",
+			input: "git cl\t\r",
+			want:  "git clone",
+		},
+		{
+			name:  "common prefix",
+			input: "git ch\t\r",
+			want:  "git che",
+		},
+		{
+			name:  "middle",
+			input: "git co x\x1b[D\x1b[D\t\r",
+			want:  "git commit x",
+		},
+		{
+			name:  "candidates",
+			input: "git c\t\r",
+			want:  "git c",
+			screen: []string{
+				"> git c",
+				"checkout     clone",
+				"cherry-pick  commit",
+				"> git c",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdin(bytes.NewBufferString(tt.input)),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+				WithSize(30, 6),
+			)
+
+			got, err := f.ReadLine("> ", WithCompletion(complete))
+			if err != nil {
+				t.Fatalf("ReadLine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ReadLine() = %q, expected %q", got, tt.want)
+			}
+
+			if tt.screen != nil {
+				if got := f.Screen().Lines()[:len(tt.screen)]; !reflect.DeepEqual(got, tt.screen) {
+					t.Fatalf("Screen() = %q, expected %q", got, tt.screen)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package console
+
+import (
+	"io"
+	"os"
+
+	"github.com/heaths/go-console/pkg/asciicast"
+)
+
+// recording records output in asciicast format.
+type recording struct {
+	w             *asciicast.Writer
+	width, height int
+}
+
+// StartRecording records everything written to Stdout and Stderr to w in
+// asciicast v2 format until StopRecording is called, including when the
+// terminal is resized. Output from other processes e.g., an external pager
+// or commands attached by Run, is not recorded. Writers returned by Stdout and
+// Stderr before recording started are not recorded either.
+func (c *con) StartRecording(w io.Writer) error {
+	width, height, err := c.Size()
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	header := asciicast.Header{
+		Width:  width,
+		Height: height,
+		Env:    make(map[string]string),
+	}
+	if c.term != "" {
+		header.Env["TERM"] = c.term
+	}
+	if shell, ok := os.LookupEnv("SHELL"); ok {
+		header.Env["SHELL"] = shell
+	}
+
+	c.recordLock.Lock()
+	defer c.recordLock.Unlock()
+
+	aw, err := asciicast.NewWriter(w, header)
+	if err != nil {
+		return err
+	}
+
+	c.recording = &recording{
+		w:      aw,
+		width:  width,
+		height: height,
+	}
+
+	return nil
+}
+
+// StopRecording stops recording output and records any incomplete UTF-8
+// sequence still held. The writer passed to StartRecording is not closed.
+func (c *con) StopRecording() error {
+	c.recordLock.Lock()
+	defer c.recordLock.Unlock()
+
+	rec := c.recording
+	if rec == nil {
+		return nil
+	}
+
+	c.recording = nil
+	return rec.w.Flush()
+}
+
+// isRecording returns whether output is being recorded.
+func (c *con) isRecording() bool {
+	c.recordLock.Lock()
+	defer c.recordLock.Unlock()
+
+	return c.recording != nil
+}
+
+// record records output if recording.
+func (c *con) record(p []byte) {
+	c.recordLock.Lock()
+	defer c.recordLock.Unlock()
+
+	rec := c.recording
+	if rec == nil {
+		return
+	}
+
+	if width, height, err := c.Size(); err == nil && (width != rec.width || height != rec.height) {
+		// nolint:errcheck
+		rec.w.WriteResize(width, height)
+		rec.width, rec.height = width, height
+	}
+
+	// nolint:errcheck
+	rec.w.WriteOutput(p)
+}
+
+// recorded gets a writer that writes to w and records what was written.
+func (c *con) recorded(w io.Writer) io.Writer {
+	return &recordingWriter{w: w, c: c}
+}
+
+type recordingWriter struct {
+	w io.Writer
+	c *con
+}
+
+func (rw *recordingWriter) Write(p []byte) (n int, err error) {
+	n, err = rw.w.Write(p)
+	if n > 0 {
+		rw.c.record(p[:n])
+	}
+	return
+}
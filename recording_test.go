@@ -0,0 +1,135 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/asciicast"
+)
+
+func TestConsole_StartRecording(t *testing.T) {
+	f := Fake(WithStdoutTTY(true), WithSize(40, 10))
+	f.term = "xterm-256color"
+	rec := &bytes.Buffer{}
+
+	write := func(s string) {
+		// nolint:errcheck
+		f.Write([]byte(s))
+	}
+
+	write("before\n")
+	if err := f.StartRecording(rec); err != nil {
+		t.Fatalf("StartRecording() error = %v", err)
+	}
+
+	write("hello\n")
+	// nolint:errcheck
+	f.Stderr().Write([]byte("warning\n"))
+
+	f.sizeOverride.Width = 60
+	f.SynchronizedUpdate(func() {
+		write("resized\n")
+	})
+
+	if err := f.StopRecording(); err != nil {
+		t.Fatalf("StopRecording() error = %v", err)
+	}
+	write("after\n")
+
+	r, err := asciicast.NewReader(rec)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	if r.Header.Width != 40 || r.Header.Height != 10 {
+		t.Fatalf("Header size = %dx%d, expected 40x10", r.Header.Width, r.Header.Height)
+	}
+	if term := r.Header.Env["TERM"]; term != "xterm-256color" {
+		t.Fatalf("Header TERM = %q, expected %q", term, "xterm-256color")
+	}
+
+	type event struct {
+		Type asciicast.EventType
+		Data string
+	}
+	var events []event
+	for {
+		e, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		events = append(events, event{e.Type, e.Data})
+	}
+
+	expected := []event{
+		{asciicast.Output, "hello\n"},
+		{asciicast.Output, "warning\n"},
+		{asciicast.Resize, "60x10"},
+		{asciicast.Output, "resized\n"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("recorded %q, expected %q", events, expected)
+	}
+}
+
+func TestConsole_StartRecording_streams(t *testing.T) {
+	f := Fake()
+
+	if f.Stdout() != f.stdout || f.Stderr() != f.stderr {
+		t.Fatal("Stdout() and Stderr() expected to return the streams when not recording")
+	}
+
+	if err := f.StartRecording(&bytes.Buffer{}); err != nil {
+		t.Fatalf("StartRecording() error = %v", err)
+	}
+	if f.Stdout() == f.stdout || f.Stderr() == f.stderr {
+		t.Fatal("Stdout() and Stderr() expected to record while recording")
+	}
+
+	if err := f.StopRecording(); err != nil {
+		t.Fatalf("StopRecording() error = %v", err)
+	}
+	if f.Stdout() != f.stdout || f.Stderr() != f.stderr {
+		t.Fatal("Stdout() and Stderr() expected to return the streams after recording")
+	}
+}
+
+func TestConsole_StopRecording_flush(t *testing.T) {
+	f := Fake()
+	rec := &bytes.Buffer{}
+
+	if err := f.StartRecording(rec); err != nil {
+		t.Fatalf("StartRecording() error = %v", err)
+	}
+	// nolint:errcheck
+	f.Write([]byte("a\xe2\x9c"))
+	if err := f.StopRecording(); err != nil {
+		t.Fatalf("StopRecording() error = %v", err)
+	}
+
+	r, err := asciicast.NewReader(rec)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var data []string
+	for {
+		e, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		data = append(data, e.Data)
+	}
+
+	// The incomplete sequence is recorded as replacement characters.
+	if expected := []string{"a", "��"}; !reflect.DeepEqual(data, expected) {
+		t.Fatalf("recorded %q, expected %q", data, expected)
+	}
+}
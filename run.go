@@ -0,0 +1,201 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/heaths/go-console/internal/text"
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// RunOption configures Run.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	capture bool
+	label   string
+}
+
+// WithCapture captures output from the command instead of attaching it to the
+// console, and shows progress with the label and the last line of output.
+func WithCapture(label string) RunOption {
+	return func(o *runOptions) {
+		o.capture = true
+		o.label = label
+	}
+}
+
+// Run runs the command and waits for it to exit.
+//
+// By default, the command is attached to Stdin, Stdout, and Stderr unless
+// already set. Any progress is paused and terminal modes are restored while
+// it runs.
+//
+// If WithCapture is specified, output is captured while progress is shown.
+// If the command fails, captured output is written to Stderr with standard
+// error in red.
+func (c *con) Run(cmd *exec.Cmd, opts ...RunOption) error {
+	o := &runOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.capture {
+		return c.runCaptured(cmd, o.label)
+	}
+
+	if cmd.Stdin == nil {
+		cmd.Stdin = c.stdin
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = c.out()
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = c.stderr
+	}
+
+	c.progressLock.Lock()
+	defer c.progressLock.Unlock()
+
+	if c.progress != nil {
+		c.progress.Stop()
+		// nolint:errcheck
+		c.progressWriter.Flush()
+		defer c.progress.Start()
+	}
+
+	resume := c.suspend()
+	defer resume()
+
+	return cmd.Run()
+}
+
+func (c *con) runCaptured(cmd *exec.Cmd, label string) error {
+	width, _, err := c.Size()
+	if err != nil || width <= 0 {
+		width = defaultWidth
+	}
+
+	cs := c.ColorScheme()
+	w := &captureWriter{
+		update: func(line string) {
+			// Leave room for the spinner and separating spaces.
+			line = text.Truncate(line, width-text.StringWidth(label)-4)
+			c.setProgressLabel(label + " " + cs.LightBlack(line))
+		},
+	}
+	cmd.Stdout = &captureStream{w: w}
+	cmd.Stderr = &captureStream{w: w, stderr: true}
+
+	c.StartProgress(label)
+	err = cmd.Run()
+	c.StopProgress()
+
+	if err != nil {
+		var sb strings.Builder
+		sb.WriteString(cs.Red(fmt.Sprintf("%s: %v", strings.Join(cmd.Args, " "), err)) + "\n")
+		for _, chunk := range w.chunks {
+			if chunk.stderr {
+				// Color each line so the color does not bleed into other output.
+				lines := strings.SplitAfter(string(chunk.b), "\n")
+				for _, line := range lines {
+					if trimmed := strings.TrimSuffix(line, "\n"); trimmed != "" {
+						sb.WriteString(cs.Red(trimmed))
+					}
+					if strings.HasSuffix(line, "\n") {
+						sb.WriteString("\n")
+					}
+				}
+			} else {
+				sb.Write(chunk.b)
+			}
+		}
+
+		fmt.Fprint(c.recorded(c.stderr), sb.String())
+	}
+
+	return err
+}
+
+// setProgressLabel changes the label of progress shown by StartProgress.
+func (c *con) setProgressLabel(label string) {
+	c.progressLock.Lock()
+	defer c.progressLock.Unlock()
+
+	if c.progress == nil {
+		return
+	}
+
+	c.progress.Lock()
+	c.progress.Suffix = " " + label
+	c.progress.Unlock()
+}
+
+type capturedChunk struct {
+	stderr bool
+	b      []byte
+}
+
+// captureWriter records output from standard output and error in the order
+// it was written, and reports the last line written.
+type captureWriter struct {
+	update func(line string)
+
+	chunks []capturedChunk
+	tail   []byte
+	last   string
+	lock   sync.Mutex
+}
+
+func (w *captureWriter) write(p []byte, stderr bool) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	b := make([]byte, len(p))
+	copy(b, p)
+	w.chunks = append(w.chunks, capturedChunk{stderr: stderr, b: b})
+
+	w.tail = append(w.tail, p...)
+	if i := bytes.LastIndexByte(w.tail, '\n'); i >= 0 {
+		lines := bytes.Split(w.tail[:i], []byte{'\n'})
+		for j := len(lines) - 1; j >= 0; j-- {
+			if line := lastLine(lines[j]); line != "" {
+				w.last = line
+				break
+			}
+		}
+		w.tail = w.tail[i+1:]
+	}
+
+	line := lastLine(w.tail)
+	if line == "" {
+		line = w.last
+	}
+	if w.update != nil {
+		w.update(line)
+	}
+
+	return len(p), nil
+}
+
+// lastLine gets the text of b after any carriage return and without escape
+// sequences or surrounding space.
+func lastLine(b []byte) string {
+	if i := bytes.LastIndexByte(bytes.TrimRight(b, "\r"), '\r'); i >= 0 {
+		b = b[i+1:]
+	}
+
+	return strings.TrimSpace(ansi.Strip(string(b)))
+}
+
+type captureStream struct {
+	w      *captureWriter
+	stderr bool
+}
+
+func (s *captureStream) Write(p []byte) (int, error) {
+	return s.w.write(p, s.stderr)
+}
@@ -0,0 +1,122 @@
+package console
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestConsole_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	f := Fake(
+		WithStdin(bytes.NewBufferString("in\n")),
+		WithStdinTTY(true),
+	)
+	if err := f.EnableRawMode(); err != nil {
+		t.Fatalf("EnableRawMode() error = %v", err)
+	}
+
+	cmd := exec.Command("sh", "-c", `read x; echo "out $x"; echo err >&2`)
+	if err := f.Run(cmd); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	stdout, stderr, _ := f.Buffers()
+	if got, want := stdout.String(), "out in\n"; got != want {
+		t.Fatalf("Stdout() = %q, expected %q", got, want)
+	}
+	if got, want := stderr.String(), "err\n"; got != want {
+		t.Fatalf("Stderr() = %q, expected %q", got, want)
+	}
+	if !f.isRaw() {
+		t.Fatal("Run() did not enable raw mode again")
+	}
+}
+
+func TestConsole_Run_capture(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tests := []struct {
+		name       string
+		script     string
+		wantErr    bool
+		wantStderr func(f *FakeConsole) string
+	}{
+		{
+			name:   "success",
+			script: "echo one; echo two >&2",
+			wantStderr: func(*FakeConsole) string {
+				return ""
+			},
+		},
+		{
+			name:    "failure",
+			script:  "echo one; sleep 0.1; echo two >&2; exit 3",
+			wantErr: true,
+			wantStderr: func(f *FakeConsole) string {
+				cs := f.ColorScheme()
+				return cs.Red("sh -c echo one; sleep 0.1; echo two >&2; exit 3: exit status 3") + "\n" +
+					"one\n" +
+					cs.Red("two") + "\n"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(WithStdoutTTY(true))
+
+			err := f.Run(exec.Command("sh", "-c", tt.script), WithCapture("working"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, expected error %v", err, tt.wantErr)
+			}
+
+			stdout, stderr, _ := f.Buffers()
+			if stdout.Len() > 0 {
+				t.Fatalf("Stdout() = %q, expected nothing", stdout.String())
+			}
+			if got, want := stderr.String(), tt.wantStderr(f); got != want {
+				t.Fatalf("Stderr() = %q, expected %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCaptureWriter(t *testing.T) {
+	var got string
+	w := &captureWriter{
+		update: func(line string) {
+			got = line
+		},
+	}
+
+	tests := []struct {
+		write  string
+		stderr bool
+		want   string
+	}{
+		{write: "building\n", want: "building"},
+		{write: "step 1\rstep 2", want: "step 2"},
+		{write: "\r\n\n", stderr: true, want: "step 2"},
+		{write: "  \x1b[32mdone\x1b[0m  ", want: "done"},
+		{write: "\nlast\n\n", want: "last"},
+	}
+
+	for _, tt := range tests {
+		// nolint:errcheck
+		w.write([]byte(tt.write), tt.stderr)
+		if got != tt.want {
+			t.Fatalf("write(%q) updated %q, expected %q", tt.write, got, tt.want)
+		}
+	}
+
+	if len(w.chunks) != len(tests) || !w.chunks[2].stderr {
+		t.Fatalf("write() captured %v, expected %d chunks", w.chunks, len(tests))
+	}
+}
@@ -4,20 +4,20 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/heaths/go-console/internal/ansi"
+	"github.com/heaths/go-console/pkg/ansi"
 )
 
 func (c *con) Reset() {
 	if c.IsStdoutTTY() {
 		// nolint:errcheck
-		c.stdout.Write([]byte(ansi.Reset))
+		c.out().Write([]byte(ansi.Reset))
 	}
 }
 
 func (c *con) ClearLine() {
 	if c.IsStdoutTTY() {
 		// nolint:errcheck
-		c.stdout.Write([]byte(ansi.CSI + "2K"))
+		c.out().Write([]byte(ansi.CSI + "2K"))
 	}
 }
 
@@ -27,64 +27,252 @@ func (c *con) ClearLines(rows int) {
 		s := strings.Repeat(ansi.CSI+"2K"+ansi.CSI+"1A", rows)
 
 		// nolint:errcheck
-		c.stdout.Write([]byte(s))
+		c.out().Write([]byte(s))
+	}
+}
+
+// ClearLinesDown clears the current row and rows below it for a total of rows,
+// leaving the cursor on the current row.
+func (c *con) ClearLinesDown(rows int) {
+	if c.IsStdoutTTY() && rows > 0 {
+		// More efficient to write once. Restore the cursor instead of moving
+		// back up since moving down from the bottom row does not move it.
+		s := ansi.CSI + "2K"
+		if rows > 1 {
+			s = ansi.CSI + "s" + strings.Repeat(ansi.CSI+"2K"+ansi.CSI+"1B", rows-1) + s + ansi.CSI + "u"
+		}
+
+		// nolint:errcheck
+		c.out().Write([]byte(s))
+	}
+}
+
+// ClearToEndOfLine clears from the cursor to the end of the current row.
+func (c *con) ClearToEndOfLine() {
+	if c.IsStdoutTTY() {
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "0K"))
+	}
+}
+
+// ClearToStartOfLine clears from the start of the current row through the
+// cursor.
+func (c *con) ClearToStartOfLine() {
+	if c.IsStdoutTTY() {
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "1K"))
+	}
+}
+
+// EraseCharacters clears characters starting at the cursor without moving
+// characters to the right of them.
+func (c *con) EraseCharacters(columns int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%dX", columns)
 	}
 }
 
 func (c *con) ClearScreen() {
 	if c.IsStdoutTTY() {
 		// nolint:errcheck
-		c.stdout.Write([]byte(ansi.CSI + "2J"))
+		c.out().Write([]byte(ansi.CSI + "2J"))
 		c.MoveCursor(1, 1)
 	}
 }
 
+// ClearToEndOfScreen clears from the cursor to the end of the screen.
+func (c *con) ClearToEndOfScreen() {
+	if c.IsStdoutTTY() {
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "0J"))
+	}
+}
+
+// ClearToStartOfScreen clears from the start of the screen through the cursor.
+func (c *con) ClearToStartOfScreen() {
+	if c.IsStdoutTTY() {
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "1J"))
+	}
+}
+
+// ClearScrollback clears lines that have scrolled off the screen, which is
+// supported by most but not all terminals.
+func (c *con) ClearScrollback() {
+	if c.IsStdoutTTY() {
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "3J"))
+	}
+}
+
 func (c *con) StartAlternativeScreenBuffer() {
 	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
 		// nolint:errcheck
-		c.stdout.Write([]byte(ansi.CSI + "?1049h"))
+		c.out().Write([]byte(ansi.CSI + "?1049h"))
+		c.modes.altScreen = true
 	}
 }
 
 func (c *con) StopAlternativeScreenBuffer() {
 	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "?1049l"))
+		c.modes.altScreen = false
+	}
+}
+
+func (c *con) HideCursor() {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "?25l"))
+		c.modes.cursorHidden = true
+	}
+}
+
+func (c *con) ShowCursor() {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "?25h"))
+		c.modes.cursorHidden = false
+	}
+}
+
+// SetTitle sets the terminal window title. The previous title is saved the
+// first time and restored by Restore.
+func (c *con) SetTitle(title string) {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		s := ansi.OSC + "0;" + title + ansi.ST
+		if !c.modes.title {
+			// Save the current title.
+			s = ansi.CSI + "22;0t" + s
+		}
+
 		// nolint:errcheck
-		c.stdout.Write([]byte(ansi.CSI + "?1049l"))
+		c.out().Write([]byte(s))
+		c.modes.title = true
 	}
 }
 
 func (c *con) MoveCursor(row, column int) {
 	if c.IsStdoutTTY() {
-		fmt.Fprintf(c.stdout, ansi.CSI+"%d;%dH", row, column)
+		fmt.Fprintf(c.out(), ansi.CSI+"%d;%dH", row, column)
 	}
 }
 
 func (c *con) CursorUp(rows int) {
 	if c.IsStdoutTTY() {
-		fmt.Fprintf(c.stdout, ansi.CSI+"%dA", rows)
+		fmt.Fprintf(c.out(), ansi.CSI+"%dA", rows)
 	}
 }
 
 func (c *con) CursorDown(rows int) {
 	if c.IsStdoutTTY() {
-		fmt.Fprintf(c.stdout, ansi.CSI+"%dB", rows)
+		fmt.Fprintf(c.out(), ansi.CSI+"%dB", rows)
 	}
 }
 
 func (c *con) CursorForward(columns int) {
 	if c.IsStdoutTTY() {
-		fmt.Fprintf(c.stdout, ansi.CSI+"%dC", columns)
+		fmt.Fprintf(c.out(), ansi.CSI+"%dC", columns)
 	}
 }
 
 func (c *con) CursorBack(columns int) {
 	if c.IsStdoutTTY() {
-		fmt.Fprintf(c.stdout, ansi.CSI+"%dD", columns)
+		fmt.Fprintf(c.out(), ansi.CSI+"%dD", columns)
 	}
 }
 
 func (c *con) CursorColumn(column int) {
 	if c.IsStdoutTTY() {
-		fmt.Fprintf(c.stdout, ansi.CSI+"%dG", column)
+		fmt.Fprintf(c.out(), ansi.CSI+"%dG", column)
+	}
+}
+
+// SetScrollRegion sets the 1-based top and bottom rows within which text
+// scrolls and moves the cursor to the top left of the screen.
+func (c *con) SetScrollRegion(top, bottom int) {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		fmt.Fprintf(c.out(), ansi.CSI+"%d;%dr", top, bottom)
+		c.modes.scrollRegion = true
+	}
+}
+
+// ResetScrollRegion resets the scroll region to the entire screen and moves
+// the cursor to the top left of the screen.
+func (c *con) ResetScrollRegion() {
+	if c.IsStdoutTTY() {
+		c.modesLock.Lock()
+		defer c.modesLock.Unlock()
+
+		// nolint:errcheck
+		c.out().Write([]byte(ansi.CSI + "r"))
+		c.modes.scrollRegion = false
+	}
+}
+
+// ScrollUp scrolls text within the scroll region up, adding blank rows at the
+// bottom.
+func (c *con) ScrollUp(rows int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%dS", rows)
+	}
+}
+
+// ScrollDown scrolls text within the scroll region down, adding blank rows at
+// the top.
+func (c *con) ScrollDown(rows int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%dT", rows)
+	}
+}
+
+// InsertLines inserts blank rows at the cursor, moving rows below it down
+// within the scroll region.
+func (c *con) InsertLines(rows int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%dL", rows)
+	}
+}
+
+// DeleteLines deletes rows at the cursor, moving rows below it up within the
+// scroll region.
+func (c *con) DeleteLines(rows int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%dM", rows)
+	}
+}
+
+// InsertCharacters inserts blank characters at the cursor, moving characters
+// to the right of it further right.
+func (c *con) InsertCharacters(columns int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%d@", columns)
+	}
+}
+
+// DeleteCharacters deletes characters at the cursor, moving characters to the
+// right of it left.
+func (c *con) DeleteCharacters(columns int) {
+	if c.IsStdoutTTY() {
+		fmt.Fprintf(c.out(), ansi.CSI+"%dP", columns)
 	}
 }
@@ -0,0 +1,122 @@
+package console
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConsole_Clear(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(*FakeConsole)
+		want []string
+		seq  string
+	}{
+		{
+			name: "ClearLine",
+			fn:   func(f *FakeConsole) { f.ClearLine() },
+			want: []string{"abcde", "", "klmno"},
+			seq:  "\x1b[2K",
+		},
+		{
+			name: "ClearLines",
+			fn:   func(f *FakeConsole) { f.ClearLines(2) },
+			want: []string{"", "", "klmno"},
+			seq:  "\x1b[2K\x1b[1A\x1b[2K\x1b[1A",
+		},
+		{
+			name: "ClearLinesDown",
+			fn:   func(f *FakeConsole) { f.ClearLinesDown(2) },
+			want: []string{"abcde", "", ""},
+			seq:  "\x1b[s\x1b[2K\x1b[1B\x1b[2K\x1b[u",
+		},
+		{
+			name: "ClearLinesDown bottom",
+			fn: func(f *FakeConsole) {
+				f.MoveCursor(3, 1)
+				f.ClearLinesDown(2)
+				f.Write([]byte("x")) // nolint:errcheck
+			},
+			want: []string{"abcde", "fghij", "x"},
+			seq:  "\x1b[3;1H\x1b[s\x1b[2K\x1b[1B\x1b[2K\x1b[ux",
+		},
+		{
+			name: "ClearToEndOfLine",
+			fn:   func(f *FakeConsole) { f.ClearToEndOfLine() },
+			want: []string{"abcde", "fg", "klmno"},
+			seq:  "\x1b[0K",
+		},
+		{
+			name: "ClearToStartOfLine",
+			fn:   func(f *FakeConsole) { f.ClearToStartOfLine() },
+			want: []string{"abcde", "   ij", "klmno"},
+			seq:  "\x1b[1K",
+		},
+		{
+			name: "EraseCharacters",
+			fn:   func(f *FakeConsole) { f.EraseCharacters(2) },
+			want: []string{"abcde", "fg  j", "klmno"},
+			seq:  "\x1b[2X",
+		},
+		{
+			name: "ClearToEndOfScreen",
+			fn:   func(f *FakeConsole) { f.ClearToEndOfScreen() },
+			want: []string{"abcde", "fg", ""},
+			seq:  "\x1b[0J",
+		},
+		{
+			name: "ClearToStartOfScreen",
+			fn:   func(f *FakeConsole) { f.ClearToStartOfScreen() },
+			want: []string{"", "   ij", "klmno"},
+			seq:  "\x1b[1J",
+		},
+		{
+			name: "ClearScrollback",
+			fn:   func(f *FakeConsole) { f.ClearScrollback() },
+			want: []string{"abcde", "fghij", "klmno"},
+			seq:  "\x1b[3J",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(
+				WithStdoutTTY(true),
+				WithSize(5, 3),
+			)
+
+			f.Write([]byte("abcdefghijklmno")) // nolint:errcheck
+			f.MoveCursor(2, 3)
+
+			stdout, _, _ := f.Buffers()
+			n := stdout.Len()
+
+			tt.fn(f)
+			if got := stdout.String()[n:]; got != tt.seq {
+				t.Fatalf("%s() wrote %q, expected %q", tt.name, got, tt.seq)
+			}
+
+			if got := f.Screen().Lines(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Lines() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_Clear_notTTY(t *testing.T) {
+	f := Fake()
+
+	f.ClearLinesDown(2)
+	f.ClearToEndOfLine()
+	f.ClearToStartOfLine()
+	f.EraseCharacters(2)
+	f.ClearToEndOfScreen()
+	f.ClearToStartOfScreen()
+	f.ClearScrollback()
+
+	stdout, _, _ := f.Buffers()
+	if got := stdout.String(); strings.Contains(got, "\x1b") {
+		t.Fatalf("wrote %q, expected nothing", got)
+	}
+}
@@ -0,0 +1,216 @@
+package console
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// WindowSize is the size of a terminal window in columns and rows.
+type WindowSize struct {
+	Width  int
+	Height int
+}
+
+// ResizeEvent is sent when the terminal of a Console created WithSession is
+// resized.
+type ResizeEvent WindowSize
+
+func (ResizeEvent) isEvent() {}
+
+// Session is a remote terminal session e.g., an SSH channel for which a
+// pseudo-terminal was requested.
+type Session struct {
+	// ReadWriter reads input from and writes output to the remote terminal.
+	io.ReadWriter
+
+	// Stderr is written to for Stderr, or ReadWriter if nil.
+	Stderr io.Writer
+
+	// Term is the TERM environment variable of the remote terminal.
+	Term string
+
+	// Width and Height are the initial size of the remote terminal.
+	Width  int
+	Height int
+
+	// Resize receives the size of the remote terminal when its window is
+	// resized. It may be nil.
+	Resize <-chan WindowSize
+}
+
+// WithSession reads input from and writes output to a remote terminal.
+// Stdin, Stdout, and Stderr are terminals the size of the remote terminal.
+// Colors are disabled if Term is "dumb".
+//
+// For example, using an SSH server package that forwards window changes:
+//
+//	pty, windows, _ := sess.Pty()
+//	resize := make(chan console.WindowSize)
+//	go func() {
+//		defer close(resize)
+//		for w := range windows {
+//			resize <- console.WindowSize{Width: w.Width, Height: w.Height}
+//		}
+//	}()
+//	c := console.New(console.WithSession(console.Session{
+//		ReadWriter: sess,
+//		Stderr:     sess.Stderr(),
+//		Term:       pty.Term,
+//		Width:      pty.Window.Width,
+//		Height:     pty.Window.Height,
+//		Resize:     resize,
+//	}))
+func WithSession(s Session) Option {
+	checkSize(s.Width, s.Height)
+	return func(c *con) {
+		tty := true
+
+		c.stdout = s.ReadWriter
+		c.stderr = s.ReadWriter
+		if s.Stderr != nil {
+			c.stderr = s.Stderr
+		}
+		c.stdin = s.ReadWriter
+
+		c.stdoutOverride = &tty
+		c.stderrOverride = &tty
+		c.stdinOverride = &tty
+
+		c.term = s.Term
+		c.sizeOverride = &size{
+			Width:  s.Width,
+			Height: s.Height,
+		}
+		c.sessionResize = s.Resize
+	}
+}
+
+// watchResize updates the size of the terminal until resize is closed.
+func (c *con) watchResize(resize <-chan WindowSize) {
+	for ws := range resize {
+		c.sizeLock.Lock()
+		c.sizeOverride = &size{
+			Width:  ws.Width,
+			Height: ws.Height,
+		}
+		c.sizeLock.Unlock()
+
+		// Notify ReadEvent without waiting if already notified.
+		select {
+		case c.resized <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Loopback is an in-process terminal session for tests. Its Console is
+// created WithSession, and tests write input to the Loopback and read what
+// the Console rendered using Output or Screen.
+type Loopback struct {
+	con    *con
+	input  *io.PipeWriter
+	resize chan WindowSize
+
+	output bytes.Buffer
+	lock   sync.Mutex
+
+	// Guards resize so it is not sent to after Close.
+	resizeLock sync.Mutex
+	closed     bool
+}
+
+// NewLoopback creates a terminal session for term the size of width and
+// height.
+func NewLoopback(term string, width, height int) *Loopback {
+	r, w := io.Pipe()
+	l := &Loopback{
+		input:  w,
+		resize: make(chan WindowSize),
+	}
+
+	l.con = New(WithSession(Session{
+		ReadWriter: struct {
+			io.Reader
+			io.Writer
+		}{r, loopbackWriter{l}},
+		Term:   term,
+		Width:  width,
+		Height: height,
+		Resize: l.resize,
+	})).(*con)
+
+	return l
+}
+
+// Console gets the Console attached to the session.
+func (l *Loopback) Console() Console {
+	return l.con
+}
+
+// Write writes input e.g., keystrokes to the session.
+func (l *Loopback) Write(p []byte) (n int, err error) {
+	return l.input.Write(p)
+}
+
+// Resize resizes the terminal as a client would when its window is resized.
+// It does nothing after Close.
+func (l *Loopback) Resize(width, height int) {
+	l.resizeLock.Lock()
+	defer l.resizeLock.Unlock()
+
+	if l.closed {
+		return
+	}
+
+	l.resize <- WindowSize{
+		Width:  width,
+		Height: height,
+	}
+}
+
+// Output gets everything the Console has written so far.
+func (l *Loopback) Output() string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.output.String()
+}
+
+// Screen renders everything the Console has written so far onto a new Screen
+// the current size of the terminal.
+func (l *Loopback) Screen() *Screen {
+	width, height, err := l.con.Size()
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	s := NewScreen(width, height)
+	// nolint:errcheck
+	s.Write([]byte(l.Output()))
+
+	return s
+}
+
+// Close ends the session. Reading input returns io.EOF.
+func (l *Loopback) Close() error {
+	l.resizeLock.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.resize)
+	}
+	l.resizeLock.Unlock()
+
+	return l.input.Close()
+}
+
+type loopbackWriter struct {
+	l *Loopback
+}
+
+func (w loopbackWriter) Write(p []byte) (n int, err error) {
+	w.l.lock.Lock()
+	defer w.l.lock.Unlock()
+
+	return w.l.output.Write(p)
+}
@@ -0,0 +1,138 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newLoopback(t *testing.T, term string) *Loopback {
+	t.Helper()
+
+	l := NewLoopback(term, 80, 24)
+	t.Cleanup(func() {
+		// nolint:errcheck
+		l.Close()
+	})
+
+	return l
+}
+
+func TestWithSession(t *testing.T) {
+	l := newLoopback(t, "xterm-256color")
+	con := l.Console()
+
+	if !con.IsStdinTTY() || !con.IsStdoutTTY() || !con.IsStderrTTY() {
+		t.Fatal("Console() is not a terminal")
+	}
+
+	if width, height, err := con.Size(); err != nil || width != 80 || height != 24 {
+		t.Fatalf("Size() = (%d, %d, %v), expected (80, 24, nil)", width, height, err)
+	}
+
+	fmt.Fprint(con, con.ColorScheme().Green("hello"))
+	if got, expected := l.Output(), "\x1b[0;32mhello\x1b[0m"; got != expected {
+		t.Fatalf("Output() = %q, expected %q", got, expected)
+	}
+	if got := l.Screen().Lines()[0]; got != "hello" {
+		t.Fatalf("Screen().Lines()[0] = %q, expected %q", got, "hello")
+	}
+}
+
+func TestWithSession_dumb(t *testing.T) {
+	l := newLoopback(t, "dumb")
+	con := l.Console()
+
+	fmt.Fprint(con, con.ColorScheme().Green("hello"))
+	if got := l.Output(); got != "hello" {
+		t.Fatalf("Output() = %q, expected %q", got, "hello")
+	}
+}
+
+func TestLoopback_Resize_closed(t *testing.T) {
+	l := newLoopback(t, "xterm-256color")
+	l.Resize(100, 30)
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Resizing or closing again after Close should be safe.
+	l.Resize(120, 40)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestWithSession_ReadEvent(t *testing.T) {
+	l := newLoopback(t, "xterm-256color")
+	con := l.Console()
+
+	tests := []struct {
+		name     string
+		send     func()
+		expected Event
+	}{
+		{
+			name: "key",
+			send: func() {
+				// nolint:errcheck
+				l.Write([]byte("a"))
+			},
+			expected: KeyEvent{Key: KeyRune, Rune: 'a'},
+		},
+		{
+			name: "resize",
+			send: func() {
+				l.Resize(100, 40)
+			},
+			expected: ResizeEvent{Width: 100, Height: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		go tt.send()
+
+		ev, err := con.ReadEvent()
+		if err != nil {
+			t.Fatalf("ReadEvent() %s error = %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(ev, tt.expected) {
+			t.Fatalf("ReadEvent() %s = %v, expected %v", tt.name, ev, tt.expected)
+		}
+	}
+
+	if width, height, _ := con.Size(); width != 100 || height != 40 {
+		t.Fatalf("Size() = (%d, %d), expected (100, 40)", width, height)
+	}
+
+	// nolint:errcheck
+	l.Close()
+	if _, err := con.ReadEvent(); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadEvent() error = %v, expected %v", err, io.EOF)
+	}
+}
+
+func TestWithSession_ReadLine(t *testing.T) {
+	l := newLoopback(t, "xterm-256color")
+	con := l.Console()
+
+	go func() {
+		// nolint:errcheck
+		l.Write([]byte("world\r"))
+	}()
+
+	got, err := con.ReadLine("name: ")
+	if err != nil {
+		t.Fatalf("ReadLine() error = %v", err)
+	}
+	if got != "world" {
+		t.Fatalf("ReadLine() = %q, expected %q", got, "world")
+	}
+	if line := l.Screen().Lines()[0]; !strings.HasPrefix(line, "name: world") {
+		t.Fatalf("Screen().Lines()[0] = %q, expected %q", line, "name: world")
+	}
+}
@@ -0,0 +1,170 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// SVGOption configures Screen.SVG.
+type SVGOption func(*svgOptions)
+
+type svgOptions struct {
+	fontFamily string
+	fontSize   float64
+	foreground string
+	background string
+}
+
+// WithSVGFont sets the font family and size in pixels. The default is a
+// monospace font 14 pixels high.
+func WithSVGFont(family string, size float64) SVGOption {
+	return func(o *svgOptions) {
+		o.fontFamily = family
+		o.fontSize = size
+	}
+}
+
+// WithSVGColors sets the default foreground and background colors in
+// "#rrggbb" or any other SVG color format. The defaults are "#e5e5e5" and
+// "#000000".
+func WithSVGColors(foreground, background string) SVGOption {
+	return func(o *svgOptions) {
+		o.foreground = foreground
+		o.background = background
+	}
+}
+
+// SVG writes an image of the Screen as SVG to w e.g., to render screenshots
+// of a FakeConsole for documentation. Colors use the default xterm palette.
+func (s *Screen) SVG(w io.Writer, opts ...SVGOption) error {
+	o := &svgOptions{
+		fontFamily: "ui-monospace, SFMono-Regular, Menlo, Consolas, monospace",
+		fontSize:   14,
+		foreground: "#e5e5e5",
+		background: "#000000",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cellWidth := o.fontSize * 0.6
+	lineHeight := o.fontSize * 1.2
+	padding := o.fontSize
+
+	width := float64(s.width)*cellWidth + 2*padding
+	height := float64(s.height)*lineHeight + 2*padding
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]s" height="%[2]s" viewBox="0 0 %[1]s %[2]s" font-family="%[3]s" font-size="%[4]s">`+"\n",
+		svgNumber(width), svgNumber(height), html.EscapeString(o.fontFamily), svgNumber(o.fontSize))
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", html.EscapeString(o.background))
+
+	var text strings.Builder
+	for row, cells := range s.cells {
+		y := padding + float64(row)*lineHeight
+
+		for col := 0; col < len(cells); {
+			style := cells[col].Style
+			fg, bg := svgColors(style, o)
+
+			// Find the run of cells with the same style.
+			end := col + 1
+			for end < len(cells) && cells[end].Style == style {
+				end++
+			}
+
+			x := padding + float64(col)*cellWidth
+			if bg != "" {
+				fmt.Fprintf(&buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+					svgNumber(x), svgNumber(y), svgNumber(float64(end-col)*cellWidth), svgNumber(lineHeight), html.EscapeString(bg))
+			}
+
+			var sb strings.Builder
+			for _, c := range cells[col:end] {
+				if c.Rune != 0 {
+					sb.WriteRune(c.Rune)
+				}
+			}
+
+			content := sb.String()
+			decorated := style.Underline || style.Strikethrough
+			if !style.Hidden && (strings.TrimRight(content, " ") != "" || decorated) {
+				if !decorated {
+					content = strings.TrimRight(content, " ")
+				}
+
+				fmt.Fprintf(&text, `<tspan x="%s" y="%s" fill="%s"%s>%s</tspan>`,
+					svgNumber(x), svgNumber(y+o.fontSize), html.EscapeString(fg), svgAttributes(style), html.EscapeString(content))
+			}
+
+			col = end
+		}
+	}
+
+	if text.Len() > 0 {
+		fmt.Fprintf(&buf, `<text xml:space="preserve">%s</text>`+"\n", text.String())
+	}
+	buf.WriteString("</svg>\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// svgColors gets the foreground and background colors of a style, or "" for
+// the default background.
+func svgColors(style ansi.Style, o *svgOptions) (fg, bg string) {
+	fg, bg = style.Foreground.Hex(), style.Background.Hex()
+	if style.Inverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = o.background
+		}
+		if bg == "" {
+			bg = o.foreground
+		}
+	}
+
+	if fg == "" {
+		fg = o.foreground
+	}
+
+	return
+}
+
+func svgAttributes(style ansi.Style) string {
+	var sb strings.Builder
+	if style.Bold {
+		sb.WriteString(` font-weight="bold"`)
+	}
+	if style.Italic {
+		sb.WriteString(` font-style="italic"`)
+	}
+	if style.Dim {
+		sb.WriteString(` opacity="0.5"`)
+	}
+
+	var decorations []string
+	if style.Underline {
+		decorations = append(decorations, "underline")
+	}
+	if style.Strikethrough {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		sb.WriteString(` text-decoration="` + strings.Join(decorations, " ") + `"`)
+	}
+
+	return sb.String()
+}
+
+// svgNumber formats n with at most 2 decimal places.
+func svgNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,23 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/golden"
+)
+
+func TestScreen_SVG(t *testing.T) {
+	f := Fake(WithStdoutTTY(true), WithSize(20, 3))
+	cs := f.ColorScheme()
+
+	f.Write([]byte(cs.ColorFunc("green+b")("✓") + " passed <ok>\r\n"))     // nolint:errcheck
+	f.Write([]byte("\x1b[7m inverse \x1b[0m \x1b[4;38;5;110mlink\x1b[0m")) // nolint:errcheck
+
+	var buf bytes.Buffer
+	if err := f.Screen().SVG(&buf, WithSVGFont("monospace", 10)); err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+
+	golden.Assert(t, "screen_svg", buf.Bytes())
+}
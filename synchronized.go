@@ -0,0 +1,176 @@
+package console
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync"
+	"sync/atomic"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// Report mode (DECRPM) response for synchronized output mode 2026.
+var synchronizedOutputResponse = regexp.MustCompile(`\x1b\[\?2026;([0-4])\$y`)
+
+// Whether synchronized output is supported.
+const (
+	synchronizedUnknown int32 = iota
+	synchronizedUnsupported
+	synchronizedSupported
+)
+
+// frameWriter buffers writes until flushed, then writes them at once. If
+// synchronized, buffered writes are wrapped in synchronized output sequences
+// so the terminal renders them together.
+type frameWriter struct {
+	w            io.Writer
+	synchronized bool
+
+	buf  bytes.Buffer
+	lock sync.Mutex
+}
+
+func (fw *frameWriter) Write(p []byte) (n int, err error) {
+	fw.lock.Lock()
+	defer fw.lock.Unlock()
+
+	return fw.buf.Write(p)
+}
+
+// Flush writes any buffered writes in a single call.
+func (fw *frameWriter) Flush() error {
+	fw.lock.Lock()
+	defer fw.lock.Unlock()
+
+	if fw.buf.Len() == 0 {
+		return nil
+	}
+
+	p := fw.buf.Bytes()
+	if fw.synchronized {
+		p = append(append([]byte(ansi.CSI+"?2026h"), p...), ansi.CSI+"?2026l"...)
+	}
+
+	fw.buf.Reset()
+	_, err := fw.w.Write(p)
+	return err
+}
+
+// BeginSynchronizedUpdate buffers writes to Stdout until
+// EndSynchronizedUpdate is called, then writes them at once. If the terminal
+// supports synchronized output, it will not render until all buffered writes
+// are processed to avoid flickering. Calls may be nested.
+func (c *con) BeginSynchronizedUpdate() {
+	// Pagers do not understand synchronized output, nor should the terminal
+	// be queried while a pager is reading input.
+	c.frameLock.Lock()
+	paging := c.pager != nil
+	c.frameLock.Unlock()
+
+	synchronized := !paging && c.IsStdoutTTY() && c.supportsSynchronizedOutput()
+
+	c.frameLock.Lock()
+	defer c.frameLock.Unlock()
+
+	c.frameDepth++
+	if c.frame == nil {
+		c.frame = &frameWriter{
+			w:            c.recorded(c.stdout),
+			synchronized: synchronized,
+		}
+		if c.pager != nil {
+			c.frame.w = c.pager
+			c.frame.synchronized = false
+		}
+	}
+}
+
+// EndSynchronizedUpdate writes everything written to Stdout since
+// BeginSynchronizedUpdate was called.
+func (c *con) EndSynchronizedUpdate() {
+	c.frameLock.Lock()
+	defer c.frameLock.Unlock()
+
+	if c.frameDepth == 0 {
+		return
+	}
+
+	c.frameDepth--
+	if c.frameDepth == 0 {
+		c.endFrame()
+	}
+}
+
+// SynchronizedUpdate calls fn between BeginSynchronizedUpdate and
+// EndSynchronizedUpdate.
+func (c *con) SynchronizedUpdate(fn func()) {
+	c.BeginSynchronizedUpdate()
+	defer c.EndSynchronizedUpdate()
+
+	fn()
+}
+
+// endFrame flushes and discards the current frame, if any.
+// The caller must hold frameLock.
+func (c *con) endFrame() {
+	if c.frame == nil {
+		return
+	}
+
+	// nolint:errcheck
+	c.frame.Flush()
+	c.frame = nil
+	c.frameDepth = 0
+}
+
+// out gets the writer for Stdout, which buffers writes during a synchronized
+// update and writes to the pager if started.
+func (c *con) out() io.Writer {
+	c.frameLock.Lock()
+	defer c.frameLock.Unlock()
+
+	if c.frame != nil {
+		return c.frame
+	}
+
+	if c.pager != nil {
+		return c.pager
+	}
+
+	if c.isRecording() {
+		return c.recorded(c.stdout)
+	}
+
+	return c.stdout
+}
+
+// supportsSynchronizedOutput queries the terminal once using DECRQM to
+// determine if synchronized output mode 2026 is supported.
+func (c *con) supportsSynchronizedOutput() bool {
+	c.synchronizedOnce.Do(func() {
+		supported := synchronizedUnsupported
+		defer func() {
+			atomic.StoreInt32(&c.synchronized, supported)
+		}()
+
+		resp, err := c.query(ansi.CSI+"?2026$p", synchronizedOutputResponse)
+		if err != nil || resp == nil {
+			return
+		}
+
+		// 0 is not recognized, 4 is permanently reset.
+		m := synchronizedOutputResponse.FindSubmatch(resp)
+		if m[1][0] >= '1' && m[1][0] <= '3' {
+			supported = synchronizedSupported
+		}
+	})
+
+	return c.knownSynchronizedOutput()
+}
+
+// knownSynchronizedOutput gets whether synchronized output is supported
+// without querying the terminal, or false if not yet queried.
+func (c *con) knownSynchronizedOutput() bool {
+	return atomic.LoadInt32(&c.synchronized) == synchronizedSupported
+}
@@ -0,0 +1,106 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+)
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestConsole_SynchronizedUpdate(t *testing.T) {
+	stdout := &countingWriter{}
+	tty := true
+	c := &con{
+		stdout:         stdout,
+		stdin:          bytes.NewBufferString("\x1b[?2026;2$y\x1b[?62c"),
+		stdoutOverride: &tty,
+		stdinOverride:  &tty,
+	}
+
+	c.SynchronizedUpdate(func() {
+		c.ClearLines(2)
+		c.Write([]byte("test")) // nolint:errcheck
+		c.SynchronizedUpdate(func() {
+			c.CursorColumn(1)
+		})
+
+		if stdout.writes != 1 {
+			t.Fatalf("SynchronizedUpdate() wrote %d times during update, expected only the query", stdout.writes)
+		}
+	})
+
+	if stdout.writes != 2 {
+		t.Fatalf("SynchronizedUpdate() wrote %d times, expected 2", stdout.writes)
+	}
+
+	want := "\x1b[?2026$p\x1b[c" + "\x1b[?2026h\x1b[2K\x1b[1A\x1b[2K\x1b[1Atest\x1b[1G\x1b[?2026l"
+	if got := stdout.String(); got != want {
+		t.Fatalf("SynchronizedUpdate() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestConsole_SynchronizedUpdate_unsupported(t *testing.T) {
+	f := Fake(
+		WithStdin(bytes.NewBufferString("\x1b[?2026;0$y\x1b[?62c")),
+		WithStdinTTY(true),
+		WithStdoutTTY(true),
+	)
+
+	f.BeginSynchronizedUpdate()
+	f.ClearLine()
+	f.EndSynchronizedUpdate()
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[?2026$p\x1b[c\x1b[2K"; got != want {
+		t.Fatalf("SynchronizedUpdate() wrote %q, expected %q", got, want)
+	}
+}
+
+func TestConsole_SynchronizedUpdate_notTTY(t *testing.T) {
+	f := Fake()
+
+	f.BeginSynchronizedUpdate()
+	f.Write([]byte("test")) // nolint:errcheck
+
+	stdout, _, _ := f.Buffers()
+	if stdout.Len() > 0 {
+		t.Fatalf("wrote %q before EndSynchronizedUpdate(), expected nothing", stdout.String())
+	}
+
+	f.EndSynchronizedUpdate()
+	if got := stdout.String(); got != "test" {
+		t.Fatalf("EndSynchronizedUpdate() wrote %q, expected %q", got, "test")
+	}
+
+	// Unbalanced calls should be ignored.
+	f.EndSynchronizedUpdate()
+}
+
+func TestConsole_Restore_synchronizedUpdate(t *testing.T) {
+	f := Fake(
+		WithStdoutTTY(true),
+	)
+
+	f.BeginSynchronizedUpdate()
+	f.HideCursor()
+	f.Restore()
+
+	stdout, _, _ := f.Buffers()
+	if got, want := stdout.String(), "\x1b[?25l\x1b[?25h"; got != want {
+		t.Fatalf("Restore() wrote %q, expected %q", got, want)
+	}
+
+	// Writes after Restore should not be buffered.
+	f.Write([]byte("test")) // nolint:errcheck
+	if got, want := stdout.String(), "\x1b[?25l\x1b[?25htest"; got != want {
+		t.Fatalf("Write() wrote %q, expected %q", got, want)
+	}
+}
@@ -0,0 +1,346 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/heaths/go-console/internal/text"
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+// Select graphics rendition (SGR) sequences to highlight search matches and
+// the status line.
+const (
+	reverse    = ansi.CSI + "7m"
+	reverseOff = ansi.CSI + "27m"
+)
+
+// tabWidth is the number of columns between tab stops.
+const tabWidth = 8
+
+// viewer is a built-in pager used when no external pager is available.
+type viewer struct {
+	c      *con
+	lines  []string
+	plain  [][]rune
+	top    int
+	width  int
+	height int
+
+	query     []rune
+	searching bool
+	input     []rune
+	message   string
+}
+
+// page shows content in the built-in pager on the alternative screen buffer.
+// Content is written directly if it fits on the screen or Stdin is not a
+// terminal.
+func (c *con) page(content []byte) error {
+	if len(content) == 0 {
+		return nil
+	}
+
+	width, height, err := c.Size()
+	if err != nil || width <= 0 || height <= 1 {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	s := strings.ReplaceAll(string(content), "\r\n", "\n")
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if !c.IsStdinTTY() || len(lines) < height {
+		_, err := c.out().Write(content)
+		return err
+	}
+
+	v := &viewer{
+		c:      c,
+		lines:  lines,
+		plain:  make([][]rune, len(lines)),
+		width:  width,
+		height: height,
+	}
+	for i, line := range lines {
+		v.plain[i] = []rune(ansi.Strip(line))
+	}
+
+	restore, err := c.makeRaw()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	c.StartAlternativeScreenBuffer()
+	defer c.StopAlternativeScreenBuffer()
+
+	c.HideCursor()
+	defer c.ShowCursor()
+
+	for {
+		v.render()
+
+		ev, err := c.ReadEvent()
+		if err != nil {
+			return err
+		}
+
+		if ev, ok := ev.(KeyEvent); ok && ev.Action != KeyRelease {
+			if v.handleKey(ev) {
+				return nil
+			}
+		}
+	}
+}
+
+// rows gets the number of rows of content shown above the status line.
+func (v *viewer) rows() int {
+	return v.height - 1
+}
+
+func (v *viewer) scroll(n int) {
+	bottom := len(v.lines) - v.rows()
+	if bottom < 0 {
+		bottom = 0
+	}
+
+	v.top = clamp(v.top+n, 0, bottom)
+}
+
+// handleKey handles a key and returns true if the user quit.
+func (v *viewer) handleKey(ev KeyEvent) bool {
+	v.message = ""
+
+	if ev.Modifiers&ModCtrl != 0 && ev.Rune == 'c' {
+		return true
+	}
+
+	if v.searching {
+		switch {
+		case ev.Key == KeyEnter:
+			v.searching = false
+			if len(v.input) > 0 {
+				v.query = v.input
+			}
+			v.search(0, 1)
+
+		case ev.Key == KeyEscape:
+			v.searching = false
+
+		case ev.Key == KeyBackspace:
+			if len(v.input) == 0 {
+				v.searching = false
+			} else {
+				v.input = v.input[:len(v.input)-1]
+			}
+
+		case ev.Key == KeyRune && ev.Modifiers&(ModCtrl|ModAlt|ModMeta) == 0:
+			v.input = append(v.input, ev.Rune)
+		}
+
+		return false
+	}
+
+	page := v.rows()
+	ctrl := ev.Modifiers&ModCtrl != 0
+	r := ev.Rune
+	if ev.Key != KeyRune || ev.Modifiers&(ModAlt|ModMeta) != 0 {
+		r = 0
+	}
+
+	switch {
+	case ev.Key == KeyEscape, !ctrl && (r == 'q' || r == 'Q'):
+		return true
+
+	case ev.Key == KeyDown, ev.Key == KeyEnter, !ctrl && r == 'j', ctrl && (r == 'n' || r == 'e'):
+		v.scroll(1)
+
+	case ev.Key == KeyUp, !ctrl && r == 'k', ctrl && (r == 'p' || r == 'y'):
+		v.scroll(-1)
+
+	case ev.Key == KeyPageDown, !ctrl && (r == ' ' || r == 'f'), ctrl && r == 'f':
+		v.scroll(page)
+
+	case ev.Key == KeyPageUp, !ctrl && r == 'b', ctrl && r == 'b':
+		v.scroll(-page)
+
+	case !ctrl && r == 'd', ctrl && r == 'd':
+		v.scroll(page / 2)
+
+	case !ctrl && r == 'u', ctrl && r == 'u':
+		v.scroll(-page / 2)
+
+	case ev.Key == KeyHome, !ctrl && (r == 'g' || r == '<'):
+		v.top = 0
+
+	case ev.Key == KeyEnd, !ctrl && (r == 'G' || r == '>'):
+		v.scroll(len(v.lines))
+
+	case !ctrl && r == '/':
+		v.searching = true
+		v.input = nil
+
+	case !ctrl && r == 'n':
+		v.search(1, 1)
+
+	case !ctrl && r == 'N':
+		v.search(-1, -1)
+	}
+
+	return false
+}
+
+// search scrolls to the first line containing the query starting offset
+// lines from the top and moving in direction dir.
+func (v *viewer) search(offset, dir int) {
+	if len(v.query) == 0 {
+		return
+	}
+
+	for i := v.top + offset; i >= 0 && i < len(v.lines); i += dir {
+		if len(v.matches(i)) > 0 {
+			v.top = 0
+			v.scroll(i)
+			return
+		}
+	}
+
+	v.message = "Pattern not found"
+}
+
+// matches gets the rune ranges of line i that match the query.
+func (v *viewer) matches(i int) [][2]int {
+	if len(v.query) == 0 {
+		return nil
+	}
+
+	caseSensitive := false
+	for _, r := range v.query {
+		if unicode.IsUpper(r) {
+			caseSensitive = true
+			break
+		}
+	}
+
+	eq := func(a, b rune) bool {
+		if caseSensitive {
+			return a == b
+		}
+		return a == b || unicode.ToLower(a) == unicode.ToLower(b)
+	}
+
+	var ranges [][2]int
+	line := v.plain[i]
+	for start := 0; start+len(v.query) <= len(line); start++ {
+		matched := true
+		for j, r := range v.query {
+			if !eq(line[start+j], r) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			ranges = append(ranges, [2]int{start, start + len(v.query)})
+			start += len(v.query) - 1
+		}
+	}
+
+	return ranges
+}
+
+func (v *viewer) render() {
+	c := v.c
+	cs := c.ColorScheme()
+
+	c.SynchronizedUpdate(func() {
+		c.MoveCursor(1, 1)
+
+		var sb strings.Builder
+		for row := 0; row < v.rows(); row++ {
+			sb.WriteString(ansi.CSI + "2K")
+			if i := v.top + row; i < len(v.lines) {
+				sb.WriteString(renderLine(v.lines[i], v.width, v.matches(i)))
+			} else {
+				sb.WriteString(cs.LightBlack("~"))
+			}
+			sb.WriteString("\r\n")
+		}
+
+		sb.WriteString(ansi.CSI + "2K")
+		sb.WriteString(reverse + text.Truncate(v.status(), v.width) + reverseOff)
+
+		fmt.Fprint(c.out(), sb.String())
+	})
+}
+
+func (v *viewer) status() string {
+	if v.searching {
+		return "/" + string(v.input)
+	}
+
+	if v.message != "" {
+		return v.message
+	}
+
+	bottom := v.top + v.rows()
+	if bottom >= len(v.lines) {
+		return fmt.Sprintf("lines %d-%d/%d (END)", v.top+1, len(v.lines), len(v.lines))
+	}
+
+	return fmt.Sprintf("lines %d-%d/%d %d%%", v.top+1, bottom, len(v.lines), bottom*100/len(v.lines))
+}
+
+// renderLine truncates line to width columns, passing through any escape
+// sequences and expanding tabs, and highlights the rune ranges in matches.
+func renderLine(line string, width int, matches [][2]int) string {
+	var sb strings.Builder
+
+	w, index, highlighted := 0, 0, false
+	for i := 0; i < len(line); {
+		if n := ansi.SequenceLength(line[i:]); n > 0 {
+			sb.WriteString(line[i : i+n])
+			if highlighted {
+				// Reapply the highlight in case the sequence reset it.
+				sb.WriteString(reverse)
+			}
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(line[i:])
+		rw, s := text.RuneWidth(r), line[i:i+size]
+		if r == '\t' {
+			// Expand tabs to the next tab stop within width.
+			rw = tabWidth - w%tabWidth
+			if w+rw > width {
+				rw = width - w
+			}
+			s = strings.Repeat(" ", rw)
+		}
+		if w+rw > width {
+			break
+		}
+		w += rw
+
+		for len(matches) > 0 && index >= matches[0][1] {
+			matches = matches[1:]
+		}
+		if inside := len(matches) > 0 && index >= matches[0][0]; inside != highlighted {
+			highlighted = inside
+			if inside {
+				sb.WriteString(reverse)
+			} else {
+				sb.WriteString(reverseOff)
+			}
+		}
+
+		sb.WriteString(s)
+		i += size
+		index++
+	}
+
+	sb.WriteString(ansi.Reset)
+	return sb.String()
+}
@@ -0,0 +1,239 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/heaths/go-console/pkg/ansi"
+)
+
+func testViewer(f *FakeConsole, lines int) *viewer {
+	v := &viewer{
+		c:      f.con,
+		width:  20,
+		height: 5,
+	}
+	for i := 1; i <= lines; i++ {
+		line := fmt.Sprintf("\x1b[32mline\x1b[0m %d", i)
+		v.lines = append(v.lines, line)
+		v.plain = append(v.plain, []rune(ansi.Strip(line)))
+	}
+	return v
+}
+
+func TestViewer(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []KeyEvent
+		want []string
+	}{
+		{
+			name: "top",
+			want: []string{"line 1", "line 2", "line 3", "line 4", "lines 1-4/10 40%"},
+		},
+		{
+			name: "down",
+			keys: []KeyEvent{{Key: KeyDown}, {Key: KeyRune, Rune: 'j'}},
+			want: []string{"line 3", "line 4", "line 5", "line 6", "lines 3-6/10 60%"},
+		},
+		{
+			name: "up",
+			keys: []KeyEvent{{Key: KeyPageDown}, {Key: KeyRune, Rune: 'k'}},
+			want: []string{"line 4", "line 5", "line 6", "line 7", "lines 4-7/10 70%"},
+		},
+		{
+			name: "bottom",
+			keys: []KeyEvent{{Key: KeyRune, Rune: 'G'}},
+			want: []string{"line 7", "line 8", "line 9", "line 10", "lines 7-10/10 (END)"},
+		},
+		{
+			name: "past bottom",
+			keys: []KeyEvent{{Key: KeyEnd}, {Key: KeyRune, Rune: ' '}},
+			want: []string{"line 7", "line 8", "line 9", "line 10", "lines 7-10/10 (END)"},
+		},
+		{
+			name: "top again",
+			keys: []KeyEvent{{Key: KeyRune, Rune: 'G'}, {Key: KeyRune, Rune: 'g'}},
+			want: []string{"line 1", "line 2", "line 3", "line 4", "lines 1-4/10 40%"},
+		},
+		{
+			name: "search input",
+			keys: []KeyEvent{{Key: KeyRune, Rune: '/'}, {Key: KeyRune, Rune: '5'}},
+			want: []string{"line 1", "line 2", "line 3", "line 4", "/5"},
+		},
+		{
+			name: "search",
+			keys: []KeyEvent{{Key: KeyRune, Rune: '/'}, {Key: KeyRune, Rune: '5'}, {Key: KeyEnter}},
+			want: []string{"line 5", "line 6", "line 7", "line 8", "lines 5-8/10 80%"},
+		},
+		{
+			name: "search next",
+			keys: []KeyEvent{{Key: KeyRune, Rune: '/'}, {Key: KeyRune, Rune: '1'}, {Key: KeyEnter}, {Key: KeyRune, Rune: 'n'}},
+			want: []string{"line 7", "line 8", "line 9", "line 10", "lines 7-10/10 (END)"},
+		},
+		{
+			name: "search previous",
+			keys: []KeyEvent{{Key: KeyRune, Rune: 'G'}, {Key: KeyRune, Rune: '/'}, {Key: KeyRune, Rune: '2'}, {Key: KeyEnter}, {Key: KeyRune, Rune: 'N'}},
+			want: []string{"line 2", "line 3", "line 4", "line 5", "lines 2-5/10 50%"},
+		},
+		{
+			name: "not found",
+			keys: []KeyEvent{{Key: KeyRune, Rune: '/'}, {Key: KeyRune, Rune: 'x'}, {Key: KeyEnter}},
+			want: []string{"line 1", "line 2", "line 3", "line 4", "Pattern not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Fake(WithStdoutTTY(true), WithSize(20, 5))
+			v := testViewer(f, 10)
+
+			for _, key := range tt.keys {
+				if v.handleKey(key) {
+					t.Fatalf("handleKey(%v) quit", key)
+				}
+			}
+			v.render()
+
+			if got := f.Screen().Lines(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Screen() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewer_quit(t *testing.T) {
+	for _, key := range []KeyEvent{
+		{Key: KeyRune, Rune: 'q'},
+		{Key: KeyEscape},
+		{Key: KeyRune, Rune: 'c', Modifiers: ModCtrl},
+	} {
+		t.Run(key.String(), func(t *testing.T) {
+			v := testViewer(Fake(), 10)
+			if !v.handleKey(key) {
+				t.Fatalf("handleKey(%v) did not quit", key)
+			}
+		})
+	}
+}
+
+func TestConsole_StartPager_builtin(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   int
+		wantAlt bool
+	}{
+		{name: "short", lines: 3},
+		{name: "long", lines: 10, wantAlt: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", "go-console-missing-pager")
+			f := Fake(
+				WithStdin(bytes.NewBufferString("jq")),
+				WithStdinTTY(true),
+				WithStdoutTTY(true),
+				WithSize(20, 5),
+			)
+
+			if err := f.StartPager(); err != nil {
+				t.Fatalf("StartPager() error = %v", err)
+			}
+			for i := 1; i <= tt.lines; i++ {
+				fmt.Fprintf(f.Stdout(), "line %d\n", i)
+			}
+
+			stdout, _, _ := f.Buffers()
+			if stdout.Len() > 0 {
+				t.Fatalf("Stdout() = %q before StopPager, expected nothing", stdout.String())
+			}
+
+			if err := f.StopPager(); err != nil {
+				t.Fatalf("StopPager() error = %v", err)
+			}
+
+			if got := strings.Contains(stdout.String(), "\x1b[?1049h"); got != tt.wantAlt {
+				t.Fatalf("StopPager() used alternative screen buffer = %v, expected %v", got, tt.wantAlt)
+			}
+			if f.Screen().AlternativeScreenBuffer() {
+				t.Fatal("StopPager() did not stop the alternative screen buffer")
+			}
+			if f.isRaw() {
+				t.Fatal("StopPager() did not disable raw mode")
+			}
+		})
+	}
+}
+
+func TestRenderLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		width   int
+		matches [][2]int
+		want    string
+	}{
+		{
+			name:  "plain",
+			line:  "hello",
+			width: 10,
+			want:  "hello\x1b[0m",
+		},
+		{
+			name:  "truncated",
+			line:  "hello world",
+			width: 5,
+			want:  "hello\x1b[0m",
+		},
+		{
+			name:  "escapes",
+			line:  "\x1b[31mhello\x1b[0m world",
+			width: 7,
+			want:  "\x1b[31mhello\x1b[0m w\x1b[0m",
+		},
+		{
+			name:  "wide",
+			line:  "世界",
+			width: 3,
+			want:  "世\x1b[0m",
+		},
+		{
+			name:  "tabs",
+			line:  "a\tb\tc",
+			width: 20,
+			want:  "a       b       c\x1b[0m",
+		},
+		{
+			name:  "tabs truncated",
+			line:  "a\tb\tc",
+			width: 12,
+			want:  "a       b   \x1b[0m",
+		},
+		{
+			name:    "matches",
+			line:    "one two one",
+			width:   20,
+			matches: [][2]int{{0, 3}, {8, 11}},
+			want:    "\x1b[7mone\x1b[27m two \x1b[7mone\x1b[0m",
+		},
+		{
+			name:    "match across escape",
+			line:    "\x1b[1mab\x1b[0mcd",
+			width:   20,
+			matches: [][2]int{{1, 3}},
+			want:    "\x1b[1ma\x1b[7mb\x1b[0m\x1b[7mc\x1b[27md\x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderLine(tt.line, tt.width, tt.matches); got != tt.want {
+				t.Fatalf("renderLine() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}